@@ -1,43 +1,51 @@
 package main
 
 import (
-	"fmt"
+	"log"
 	"time"
 
 	"github.com/smartystreets/pbar/v2"
 )
 
 func main() {
-	fmt.Println()
-	time.Sleep(time.Millisecond * 250) // wait for the println to finish before starting the progressbar
-
-	// get a new progress bar with all possible options
-	progress := pbar.NewPBar(8000,
-		pbar.BarLabel("File 1: "), pbar.RefreshIntervalMilliseconds(750), pbar.BarLength(25),
-		pbar.BarLeft('⁅'), pbar.BarRight('⁆'), pbar.BarUncompleted('▭'), pbar.BarCompleted('▬'))
-	// start the progress bar thread which updates the bar at the refresh interval
-	progress.Start()
-	fmt.Println()
-
-	// get a second progress bar with all possible options
-	progress2 := pbar.NewPBar(5000,
-		pbar.BarLabel("File 2: "), pbar.RefreshIntervalMilliseconds(750), pbar.BarLength(25),
-		pbar.BarLeft('⁅'), pbar.BarRight('⁆'), pbar.BarUncompleted('▭'), pbar.BarCompleted('▬'))
-	progress2.Start()
+	// the container owns the terminal lines and repaints every bar it holds in a single frame
+	container := pbar.NewContainer(pbar.RefreshIntervalMilliseconds(250))
+
+	// register a custom theme bundling the graphic characters used by both bars
+	pbar.RegisterTheme("rectangles", pbar.Theme{Left: '⁅', Right: '⁆', Uncompleted: '▭', Completed: '▬'})
+
+	// get a new progress bar using the custom theme
+	progress := pbar.NewPBar(8000, pbar.BarLabel("File 1: "), pbar.BarLength(25), pbar.WithTheme("rectangles"))
+	container.Add(progress)
+
+	// get a second progress bar using a built-in theme
+	progress2 := pbar.NewPBar(5000, pbar.BarLabel("File 2: "), pbar.BarLength(25), pbar.WithTheme(pbar.ThemeShaded))
+	container.Add(progress2)
+
+	// start the render thread which updates all bars at the refresh interval
+	container.Start()
+
+	// print log messages above the bars rather than over them
+	log.SetOutput(container.Bypass())
 
 	// simulate doing some stuff
 	for i := 0; i <= 8000; i++ {
-		if i <= progress.TargetCount {
+		if i <= progress.Target() {
 			progress.Update(i)               // update the counter in the progress bar
 			time.Sleep(time.Millisecond / 2) // make it look like we are doing something important
 		}
 
-		if i <= progress2.TargetCount {
+		if i <= progress2.Target() {
 			progress2.Update(i)
 			time.Sleep(time.Millisecond / 2)
 		}
+
+		if i == progress2.Target() {
+			log.Println("File 2 complete")
+		}
 	}
 
-	progress.Finish() // update progress bar for the final time and terminate the thread
+	progress.Finish() // mark the bars complete
 	progress2.Finish()
+	container.Stop() // paint the final frame and terminate the thread
 }
@@ -0,0 +1,156 @@
+package pbar
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Color holds the SGR (Select Graphic Rendition) parameters of an ANSI color or style,
+// e.g. Red, Color256(208), ColorRGB(255, 128, 0) or Bold(Green).
+type Color string
+
+const (
+	Black   Color = "30"
+	Red     Color = "31"
+	Green   Color = "32"
+	Yellow  Color = "33"
+	Blue    Color = "34"
+	Magenta Color = "35"
+	Cyan    Color = "36"
+	White   Color = "37"
+
+	BrightBlack   Color = "90"
+	BrightRed     Color = "91"
+	BrightGreen   Color = "92"
+	BrightYellow  Color = "93"
+	BrightBlue    Color = "94"
+	BrightMagenta Color = "95"
+	BrightCyan    Color = "96"
+	BrightWhite   Color = "97"
+)
+
+// Color256 selects a foreground color from the 256-color palette.
+func Color256(index uint8) Color {
+	return Color(fmt.Sprintf("38;5;%d", index))
+}
+
+// ColorRGB selects a 24-bit (truecolor) foreground color.
+func ColorRGB(red, green, blue uint8) Color {
+	return Color(fmt.Sprintf("38;2;%d;%d;%d", red, green, blue))
+}
+
+// RGB is a 24-bit color, used where colors are blended, as in BarCompletedGradient.
+type RGB struct{ Red, Green, Blue uint8 }
+
+func (this RGB) Color() Color { return ColorRGB(this.Red, this.Green, this.Blue) }
+
+// blend interpolates between two colors; position 0 gives this and 1 gives other.
+func (this RGB) blend(other RGB, position float64) RGB {
+	mix := func(from, to uint8) uint8 { return uint8(float64(from) + (float64(to)-float64(from))*position + 0.5) }
+	return RGB{mix(this.Red, other.Red), mix(this.Green, other.Green), mix(this.Blue, other.Blue)}
+}
+
+// ColorThreshold applies Color to the completed cells once the bar reaches Percent.
+type ColorThreshold struct {
+	Percent int
+	Color   Color
+}
+
+// Bold adds the bold attribute to a color.
+func Bold(color Color) Color { return "1;" + color }
+
+// Underline adds the underline attribute to a color.
+func Underline(color Color) Color { return "4;" + color }
+
+// colors holds the styling applied to each part of the line.
+type colors struct {
+	completed, uncompleted, brackets, label, percent Color
+	thresholds                                       []ColorThreshold // in ascending order of Percent
+	gradient                                         []RGB            // the first and last cell colors
+
+	force   bool // color even when the output is not a terminal
+	enabled bool
+}
+
+// paint wraps text in the escape sequences for color, or returns it as-is
+// when the color is unset or coloring is disabled.
+func (this *colors) paint(color Color, text string) string {
+	if !this.enabled || color == "" || text == "" {
+		return text
+	}
+	return fmt.Sprintf("%c[%sm%s%c[0m", 27, color, text, 27)
+}
+
+// enable decides whether to color the output: only for terminals, and never when
+// the NO_COLOR environment variable is set (https://no-color.org), unless color is forced.
+func (this *colors) enable(output io.Writer, noColor string) {
+	this.enabled = this.force || (noColor == "" && isTerminal(output))
+}
+
+func isTerminal(output io.Writer) bool {
+	file, ok := output.(*os.File)
+	return ok && isTerminalFile(file)
+}
+
+// colorBar renders barVisual with the bracket, completed and uncompleted colors.
+func (this *PBarSupport) colorBar() string {
+	last := len(this.barVisual) - 1
+	from, to := max(this.filledFrom, 1), min(this.filledTo, last-1)
+	if from > to {
+		from, to = 1, 0 // nothing filled
+	}
+	rest := to + 1
+	if this.headTo > 0 {
+		rest = this.headTo + 1
+	}
+
+	var bar strings.Builder
+	bar.WriteString(this.colors.paint(this.colors.brackets, string(this.barVisual[0])))
+	bar.WriteString(this.colors.paint(this.colors.uncompleted, this.cells(this.barVisual[1:from])))
+	if len(this.colors.gradient) == 2 && this.colors.enabled {
+		for cell := from; cell <= to; cell++ {
+			bar.WriteString(this.colors.paint(this.gradientColor(cell), this.cells(this.barVisual[cell:cell+1])))
+		}
+		if this.headTo > 0 {
+			bar.WriteString(this.colors.paint(this.gradientColor(this.headTo), this.paddedHead()))
+		}
+	} else {
+		bar.WriteString(this.colors.paint(this.completedColor(), this.cells(this.barVisual[from:to+1])))
+		if this.headTo > 0 {
+			bar.WriteString(this.colors.paint(this.completedColor(), this.paddedHead()))
+		}
+	}
+	bar.WriteString(this.colors.paint(this.colors.uncompleted, this.cells(this.barVisual[rest:last])))
+	bar.WriteString(this.colors.paint(this.colors.brackets, string(this.barVisual[last])))
+	return bar.String()
+}
+
+// paddedHead renders the head, padded to fill the cells it covers.
+func (this *PBarSupport) paddedHead() string {
+	covered := (this.headTo - this.headFrom + 1) * max(this.cellWidth, 1)
+	return this.head + strings.Repeat(" ", max(covered-displayWidth(this.head), 0))
+}
+
+// completedColor returns the color of the highest threshold the bar has reached,
+// or the completed color when no threshold applies.
+func (this *PBarSupport) completedColor() Color {
+	color := this.colors.completed
+	percent := int(this.fraction * 100)
+	for _, threshold := range this.colors.thresholds {
+		if percent >= threshold.Percent {
+			color = threshold.Color
+		}
+	}
+	return color
+}
+
+// gradientColor blends the gradient colors by the cell's position across the whole bar.
+func (this *PBarSupport) gradientColor(cell int) Color {
+	position := 0.0
+	if this.barLength > 1 {
+		position = float64(cell-1) / float64(this.barLength-1)
+	}
+	return this.colors.gradient[0].blend(this.colors.gradient[1], position).Color()
+}
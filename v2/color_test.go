@@ -0,0 +1,131 @@
+package pbar
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/smarty/assertions/should"
+	"github.com/smarty/gunit"
+)
+
+func TestColorFixture(t *testing.T) {
+	gunit.Run(new(ColorFixture), t)
+}
+
+type ColorFixture struct {
+	*gunit.Fixture
+}
+
+func (this *ColorFixture) TestColorCodes() {
+	this.So(Bold(Red), should.Equal, Color("1;31"))
+	this.So(Color256(208), should.Equal, Color("38;5;208"))
+	this.So(ColorRGB(255, 128, 0), should.Equal, Color("38;2;255;128;0"))
+}
+
+func (this *ColorFixture) TestColoredLine() {
+	progressBar := NewPBar(100, OutputWriter(new(bytes.Buffer)), ForceColor(), BarLength(4), BarLabel("load "),
+		BarCompletedColor(Green), BarUncompletedColor(BrightBlack), BarBracketColor(Blue),
+		BarLabelColor(Bold(White)), BarPercentColor(Yellow))
+	progressBar.initializeBar()
+	progressBar.Update(50)
+	progressBar.updateBar()
+
+	this.So(progressBar.line(), should.Equal, "\x1b[1;37mload \x1b[0m"+
+		"\x1b[34m[\x1b[0m\x1b[32m==\x1b[0m\x1b[90m  \x1b[0m\x1b[34m]\x1b[0m"+
+		" (50/100) \x1b[33m50%\x1b[0m")
+	this.So(displayWidth(progressBar.line()), should.Equal, len("load [==  ] (50/100) 50%"))
+}
+
+func (this *ColorFixture) TestIndeterminateSegmentIsColored() {
+	progressBar := NewPBar(0, OutputWriter(new(bytes.Buffer)), ForceColor(), BarLength(5),
+		BarCompletedColor(Green), BarUncompleted('.'), BarCompleted('#'))
+	progressBar.initializeBar()
+	progressBar.updateBar()
+
+	this.So(progressBar.bar(), should.Equal, "[.\x1b[32m#\x1b[0m...]")
+}
+
+func (this *ColorFixture) TestColorIsOnlyWrittenToTerminals() {
+	var palette colors
+
+	palette.enable(new(bytes.Buffer), "")
+	this.So(palette.enabled, should.BeFalse)
+
+	palette.force = true
+	palette.enable(new(bytes.Buffer), "1")
+	this.So(palette.enabled, should.BeTrue)
+}
+
+func (this *ColorFixture) TestContainedBarsFollowTheContainerOutput() {
+	progressBar := NewPBar(10, BarLength(2), BarCompletedColor(Green))
+	progressBar.colors.enabled = true // as when stdout is a terminal
+	forced := NewPBar(10, BarLength(2), ForceColor())
+
+	container := NewContainer(OutputWriter(new(bytes.Buffer)))
+	container.Add(progressBar)
+	container.Add(forced)
+
+	this.So(progressBar.colors.enabled, should.BeFalse)
+	this.So(forced.colors.enabled, should.BeTrue)
+}
+
+func (this *ColorFixture) TestNoColorDisablesTerminals() {
+	terminal, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0) // the controlling side of a pseudo-terminal
+	if err != nil {
+		return // pseudo-terminals are not available
+	}
+	defer func() { _ = terminal.Close() }()
+
+	var palette colors
+	palette.enable(terminal, "1")
+	this.So(palette.enabled, should.BeFalse)
+
+	palette.enable(terminal, "")
+	this.So(palette.enabled, should.BeTrue)
+}
+
+func (this *ColorFixture) TestCharacterDevicesOtherThanTerminalsAreNotColored() {
+	device, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	this.So(err, should.BeNil)
+	defer func() { _ = device.Close() }()
+
+	var palette colors
+	palette.enable(device, "")
+	this.So(palette.enabled, should.BeFalse)
+	this.So(isTerminal(device), should.BeFalse)
+}
+
+func (this *ColorFixture) TestThresholdsChangeTheCompletedColor() {
+	progressBar := NewPBar(100, OutputWriter(new(bytes.Buffer)), ForceColor(), BarLength(4),
+		BarCompletedColor(Blue), BarCompletedColorThresholds(
+			ColorThreshold{Percent: 66, Color: Green},
+			ColorThreshold{Percent: 33, Color: Yellow},
+			ColorThreshold{Percent: 30, Color: Red},
+		))
+	progressBar.initializeBar()
+
+	for current, expected := range map[int]string{
+		25:  "[\x1b[34m=\x1b[0m   ]",
+		30:  "[\x1b[31m=\x1b[0m   ]",
+		50:  "[\x1b[33m==\x1b[0m  ]",
+		100: "[\x1b[32m====\x1b[0m]",
+	} {
+		progressBar.Update(current)
+		progressBar.updateBar()
+		this.So(progressBar.bar(), should.Equal, expected)
+	}
+}
+
+func (this *ColorFixture) TestGradientSpansTheBar() {
+	progressBar := NewPBar(100, OutputWriter(new(bytes.Buffer)), ForceColor(), BarLength(3),
+		BarCompletedGradient(RGB{255, 0, 0}, RGB{0, 0, 255}))
+	progressBar.initializeBar()
+	progressBar.Update(100)
+	progressBar.updateBar()
+
+	this.So(progressBar.bar(), should.Equal, "["+
+		"\x1b[38;2;255;0;0m=\x1b[0m"+
+		"\x1b[38;2;128;0;128m=\x1b[0m"+
+		"\x1b[38;2;0;0;255m=\x1b[0m]")
+}
@@ -0,0 +1,337 @@
+package pbar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bar is implemented by *PBar[T] for every integer T, which allows bars
+// counting different types to share a single Container.
+type Bar interface {
+	animated() bool
+	attach(owner *Container)
+	cancel()
+	changed() bool
+	ended() bool
+	frame() string
+	lastFrame() string
+	progress() (current, target int64)
+	plainFrame(final bool) string
+	resize(columns int)
+	settle(final bool)
+	settling() bool
+}
+
+// Container owns a contiguous block of terminal lines and repaints every
+// Bar added to it, one line per bar, from a single render goroutine.
+// Bars added to a Container must not be started individually.
+type Container struct {
+	PBarSupport
+	mutex   sync.Mutex
+	bars    []Bar
+	lines   int          // number of lines painted by the previous frame
+	dirty   bool         // bars have been added or removed since the previous frame
+	final   bool         // the next frame is the last
+	running bool         // Start has been called
+	closed  bool         // the final frame has been painted
+	settled map[Bar]bool // bars that had ended when the previous frame was painted
+	once    sync.Once
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewContainer accepts the same options as NewPBar. Only the output writer
+// and refresh interval apply to the Container itself.
+func NewContainer(options ...Option) *Container {
+	this := &Container{
+		PBarSupport: DefaultPBarSupport(),
+		stop:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+
+	for _, configure := range options {
+		configure(&this.PBarSupport)
+	}
+
+	return this
+}
+
+// [locks mutex]
+func (this *Container) Add(bar Bar) {
+	bar.attach(this)
+
+	this.mutex.Lock()
+	if this.columns > 0 {
+		bar.resize(this.columns)
+	}
+	this.bars = append(this.bars, bar)
+	this.dirty = true
+	this.mutex.Unlock()
+
+	this.wakeUp()
+}
+
+// [locks mutex]
+func (this *Container) Remove(bar Bar) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for i, contained := range this.bars {
+		if contained == bar {
+			this.bars = append(this.bars[:i], this.bars[i+1:]...)
+			this.dirty = true
+			this.wakeUp()
+			bar.settle(true) // the bar is no longer painted, so nothing is left to wait for
+			return
+		}
+	}
+}
+
+func (this *Container) Start() {
+	this.StartContext(context.Background())
+}
+
+// StartContext is like Start, but cancelling ctx marks every unfinished bar as
+// cancelled, paints a final frame and stops the render goroutine.
+// [locks mutex]
+func (this *Container) StartContext(ctx context.Context) {
+	this.mutex.Lock()
+	if this.running {
+		this.mutex.Unlock()
+		return // the Container is already running
+	}
+	this.running = true
+	this.mutex.Unlock()
+
+	this.checkTty()
+	go this.start(ctx)
+}
+
+// start repaints when a bar changes, at most once per refresh interval, and
+// immediately when a bar ends.
+func (this *Container) start(ctx context.Context) {
+	defer close(this.stopped)
+
+	var resized <-chan os.Signal
+	if this.interactive {
+		this.measureWidth()
+		watching, stopWatching := watchResize()
+		defer stopWatching()
+		resized = watching
+	}
+
+	var painted time.Time
+	for {
+		if this.changed() {
+			this.repaint()
+			painted = time.Now()
+		}
+
+		if !this.await(ctx, this.stop, painted, resized, this) {
+			if ctx.Err() != nil {
+				this.cancel()
+			}
+			this.repaintFinal()
+			return
+		}
+	}
+}
+
+// measureWidth passes the width of the terminal on to every bar, for those using AutoWidth.
+// [locks mutex]
+func (this *Container) measureWidth() {
+	columns, err := terminalColumns(this.tty)
+	if err != nil {
+		return
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.columns = columns
+	this.dirty = true
+	for _, bar := range this.bars {
+		bar.resize(columns)
+	}
+}
+
+// changed reports whether the next frame may differ from the previous one.
+// [locks mutex]
+func (this *Container) changed() bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.dirty {
+		return true
+	}
+	for _, bar := range this.bars {
+		if bar.changed() {
+			return true
+		}
+	}
+	return false
+}
+
+// animated reports whether any bar changes with time alone.
+// [locks mutex]
+func (this *Container) animated() bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for _, bar := range this.bars {
+		if bar.animated() {
+			return true
+		}
+	}
+	return false
+}
+
+// ended reports whether a bar has ended since the previous frame.
+// [locks mutex]
+func (this *Container) ended() bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for _, bar := range this.bars {
+		if bar.ended() && !this.settled[bar] || bar.settling() {
+			return true
+		}
+	}
+	return false
+}
+
+// [locks mutex]
+func (this *Container) cancel() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	for _, bar := range this.bars {
+		bar.cancel()
+	}
+}
+
+// Stop paints a final frame of every bar and waits for the render goroutine to exit.
+// Stop may also be called after the context given to StartContext is cancelled, and
+// returns immediately if the Container was never started.
+func (this *Container) Stop() {
+	if !this.started() {
+		return
+	}
+	this.once.Do(func() { close(this.stop) })
+	this.Wait()
+}
+
+// Done returns a channel that is closed once the render goroutine has painted its final frame.
+func (this *Container) Done() <-chan struct{} {
+	return this.stopped
+}
+
+// Wait blocks until the render goroutine has painted its final frame, after Stop
+// or after the context given to StartContext is cancelled. Wait returns immediately
+// if the Container was never started.
+func (this *Container) Wait() {
+	if !this.started() {
+		return
+	}
+	<-this.stopped
+}
+
+// [locks mutex]
+func (this *Container) started() bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	return this.running
+}
+
+// painting reports whether the render goroutine has been started and is yet to paint its final frame.
+// [locks mutex]
+func (this *Container) painting() bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	return this.running && !this.closed
+}
+
+// [locks mutex]
+func (this *Container) repaintFinal() {
+	this.mutex.Lock()
+	this.final = true
+	this.mutex.Unlock()
+
+	this.repaint()
+}
+
+// [locks mutex]
+func (this *Container) repaint() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	defer this.settleBars()
+
+	this.settled = make(map[Bar]bool)
+	for _, bar := range this.bars {
+		this.settled[bar] = bar.ended() // and so painted as ended by this frame
+	}
+
+	if this.plain {
+		this.paintPlain()
+		return
+	}
+
+	var frame strings.Builder
+	if this.lines > 0 {
+		_, _ = fmt.Fprintf(&frame, "%c[%dA", 27, this.lines) // back to the first line of the block
+	}
+	this.paintBars(&frame, Bar.frame)
+	_, _ = fmt.Fprintf(&frame, "%c[J", 27) // erase lines left over from removed bars
+	this.dirty = false
+	this.closed = this.closed || this.final
+
+	_, _ = io.WriteString(this.output, frame.String())
+}
+
+// settleBars tells each bar that a frame has been painted, which closes the Done channel
+// of bars whose final frame it was.
+func (this *Container) settleBars() {
+	for _, bar := range this.bars {
+		bar.settle(this.final)
+	}
+}
+
+// paintBars renders one line for each bar, leaving the cursor below the block.
+func (this *Container) paintBars(frame *strings.Builder, render func(Bar) string) {
+	lines := 0
+	for _, bar := range this.bars {
+		if line := render(bar); line != "" { // cleared bars give up their line
+			_, _ = fmt.Fprintf(frame, "%c%c[2K%s\n", 13, 27, line)
+			lines += this.rowsFor(displayWidth(line))
+		}
+	}
+	this.lines = lines
+}
+
+// paintPlain writes the line of each bar for which a plain line is due.
+func (this *Container) paintPlain() {
+	var lines strings.Builder
+	for _, bar := range this.bars {
+		if line := bar.plainFrame(this.final); line != "" {
+			lines.WriteString(line + "\n")
+		}
+	}
+	this.dirty = false
+
+	_, _ = io.WriteString(this.output, lines.String())
+}
+
+// [locks mutex]
+func (this *Container) checkTty() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.checkOutput()
+}
@@ -0,0 +1,212 @@
+package pbar
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smarty/assertions/should"
+	"github.com/smarty/gunit"
+)
+
+func TestContainerFixture(t *testing.T) {
+	gunit.Run(new(ContainerFixture), t)
+}
+
+type ContainerFixture struct {
+	*gunit.Fixture
+	output    *bytes.Buffer
+	container *Container
+}
+
+func (this *ContainerFixture) Setup() {
+	this.output = new(bytes.Buffer)
+	this.container = NewContainer(OutputWriter(this.output), RefreshIntervalMilliseconds(10))
+}
+
+func (this *ContainerFixture) lastFrame() string {
+	frames := strings.Split(this.output.String(), "\x1b[J")
+	return frames[len(frames)-2]
+}
+
+func (this *ContainerFixture) TestBarsOfDifferentTypesShareOneFrame() {
+	first := NewPBar(1000, BarLabel("one "), BarLength(5))
+	second := NewPBar(uint8(10), BarLabel("two "), BarLength(5))
+	this.container.Add(first)
+	this.container.Add(second)
+	this.container.Start()
+
+	first.Update(500)
+	second.Finish()
+	this.container.Stop()
+
+	this.So(this.lastFrame(), should.Equal, "\x1b[2A"+
+		"\r\x1b[2Kone [==   ] (500/1,000) 50%\n"+
+		"\r\x1b[2Ktwo [=====] (10/10) 100%\n")
+}
+
+func (this *ContainerFixture) TestFirstFrameDoesNotMoveCursor() {
+	this.container.Add(NewPBar(10, BarLength(2)))
+	this.container.Start()
+	this.container.Stop()
+
+	this.So(this.output.String(), should.StartWith, "\r\x1b[2K[  ] (0/10) 0%\n\x1b[J")
+}
+
+func (this *ContainerFixture) TestRemovedBarsAreErased() {
+	first := NewPBar(10, BarLength(2))
+	second := NewPBar(10, BarLength(2))
+	this.container.Add(first)
+	this.container.Add(second)
+	this.container.repaint()
+	this.container.Remove(first)
+	this.container.repaint()
+
+	this.So(this.lastFrame(), should.Equal, "\x1b[2A\r\x1b[2K[  ] (0/10) 0%\n")
+}
+
+func (this *ContainerFixture) TestWrappedLinesAreCountedByRow() {
+	this.container.columns = 10
+	this.container.Add(NewPBar(10, BarLength(2)))
+	this.container.repaint()
+	this.container.repaint()
+
+	this.So(this.lastFrame(), should.Equal, "\x1b[2A\r\x1b[2K[  ] (0/10) 0%\n")
+}
+
+func (this *ContainerFixture) TestStartingTwiceRunsOneRenderGoroutine() {
+	this.container.Add(NewPBar(10, BarLength(2)))
+	this.container.Start()
+	this.container.Start()
+	this.container.Stop()
+
+	this.So(strings.Count(this.output.String(), "\x1b[J"), should.BeGreaterThan, 0)
+}
+
+func (this *ContainerFixture) TestStopWithoutStartReturns() {
+	this.container.Add(NewPBar(10, BarLength(2)))
+
+	this.container.Stop()
+	this.container.Wait()
+
+	this.So(this.output.String(), should.BeEmpty)
+}
+
+func (this *ContainerFixture) TestContainedBarIgnoresStart() {
+	bar := NewPBar(10, OutputWriter(this.output))
+	this.container.Add(bar)
+	bar.Start()
+	bar.Finish()
+
+	this.So(this.output.Len(), should.Equal, 0)
+}
+
+func (this *ContainerFixture) TestContextCancellationMarksUnfinishedBars() {
+	done := NewPBar(10, BarLength(2))
+	pending := NewPBar(10, BarLength(2))
+	this.container.Add(done)
+	this.container.Add(pending)
+	ctx, cancel := context.WithCancel(context.Background())
+	this.container.StartContext(ctx)
+
+	done.Finish()
+	pending.Update(5)
+	cancel()
+	this.container.Wait()
+
+	this.So(this.lastFrame(), should.Equal, "\x1b[2A"+
+		"\r\x1b[2K[==] (10/10) 100%\n"+
+		"\r\x1b[2K[= ] (5/10) cancelled\n")
+}
+
+func (this *ContainerFixture) TestClearedBarsGiveUpTheirLine() {
+	first := NewPBar(10, BarLength(2), ClearOnAbort())
+	second := NewPBar(10, BarLength(2))
+	this.container.Add(first)
+	this.container.Add(second)
+	this.container.repaint()
+	first.Abort()
+	this.container.repaint()
+
+	this.So(this.lastFrame(), should.Equal, "\x1b[2A\r\x1b[2K[  ] (0/10) 0%\n")
+}
+
+func (this *ContainerFixture) TestIdleContainerIsNotRepainted() {
+	this.container.Add(NewPBar(10, BarLength(2)))
+	this.container.Start()
+	time.Sleep(time.Millisecond * 50)
+	this.container.Stop()
+
+	this.So(strings.Count(this.output.String(), "\x1b[J"), should.Equal, 2) // the first and final frames
+}
+
+func (this *ContainerFixture) TestChangesArePaintedWithoutWaitingForTheInterval() {
+	container := NewContainer(OutputWriter(this.output), RefreshIntervalMilliseconds(300))
+	bar := NewPBar(10, BarLength(2))
+	container.Add(bar)
+	container.Start()
+	defer container.Stop()
+	time.Sleep(time.Millisecond * 350) // idle for longer than the interval
+
+	frame := func() string {
+		container.mutex.Lock()
+		defer container.mutex.Unlock()
+		frames := strings.Split(this.output.String(), "\x1b[J")
+		return frames[len(frames)-2]
+	}
+
+	bar.Update(5)
+	time.Sleep(time.Millisecond * 20)
+	this.So(frame(), should.EndWith, "[= ] (5/10) 50%\n")
+
+	bar.Update(6) // throttled until the interval passes
+	time.Sleep(time.Millisecond * 20)
+	this.So(frame(), should.EndWith, "[= ] (5/10) 50%\n")
+
+	bar.Update(10) // completion is painted immediately
+	time.Sleep(time.Millisecond * 20)
+	this.So(frame(), should.EndWith, "[==] (10/10) 100%\n")
+}
+
+func (this *ContainerFixture) TestFinishWaitsForTheContainerToPaintTheFinalFrame() {
+	container := NewContainer(OutputWriter(this.output), RefreshIntervalMilliseconds(300))
+	bar := NewPBar(10, BarLength(2))
+	container.Add(bar)
+	container.Start()
+	defer container.Stop()
+
+	bar.Update(10)
+	time.Sleep(time.Millisecond * 20)
+	started := time.Now()
+	bar.Finish() // the frame is no different, but is painted without waiting for the interval
+
+	this.So(time.Since(started), should.BeLessThan, time.Millisecond*150)
+	select {
+	case <-bar.Done():
+	default:
+		this.Error("Done was not closed by the final frame")
+	}
+}
+
+func (this *ContainerFixture) TestStopPaintsTheFinalFrameOfUnfinishedBars() {
+	bar := NewPBar(10, BarLength(2))
+	this.container.Add(bar)
+	this.container.Start()
+
+	this.container.Stop()
+	bar.Wait()
+
+	this.So(this.lastFrame(), should.Equal, "\x1b[1A\r\x1b[2K[  ] (0/10) 0%\n")
+}
+
+func (this *ContainerFixture) TestWaitReturnsBeforeTheContainerIsStarted() {
+	bar := NewPBar(10, BarLength(2))
+	this.container.Add(bar)
+
+	bar.Wait()
+	bar.Finish()
+
+	this.So(this.output.String(), should.BeEmpty)
+}
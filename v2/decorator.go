@@ -0,0 +1,62 @@
+package pbar
+
+import (
+	"strings"
+	"time"
+)
+
+// Snapshot describes the state of a bar at the moment a frame is rendered.
+type Snapshot struct {
+	Current int64
+	Target  int64
+	Elapsed time.Duration
+	Rate    float64 // smoothed items per second
+	Width   int     // bar length in cells, not counting the left and right markers; with AutoWidth, the length before the frame is fitted
+}
+
+// Decorator renders a custom field beside the bar. Each decorator is called once per frame,
+// while the bar is locked, from whichever goroutine renders the frame: usually the render
+// goroutine, but also the caller of Container.Add, Finish, Abort or Fail. Decorators must
+// not call methods on the bar. Lines printed through Writer, Bypass or a LogHandler repaint
+// the output of the previous call rather than calling the decorators again.
+type Decorator interface {
+	Decorate(Snapshot) string
+}
+
+// DecoratorFunc adapts an ordinary function to the Decorator interface.
+type DecoratorFunc func(Snapshot) string
+
+func (this DecoratorFunc) Decorate(snapshot Snapshot) string { return this(snapshot) }
+
+// runDecorators calls the decorators for the frame being rendered, keeping their output
+// for every rendering of its line, including the one AutoWidth measures the line with.
+func (this *PBarSupport) runDecorators() {
+	this.prefixes, this.suffixes = this.prefixes[:0], this.suffixes[:0]
+	for _, decorator := range this.prepended {
+		this.prefixes = appendNonEmpty(this.prefixes, decorator.Decorate(this.snapshot))
+	}
+	for _, decorator := range this.appended {
+		this.suffixes = appendNonEmpty(this.suffixes, decorator.Decorate(this.snapshot))
+	}
+}
+
+// decorate surrounds the templated line with the prepended and appended decorator
+// output, separated by spaces. Decorators that return "" are skipped.
+func (this *PBarSupport) decorate(line string) string {
+	if len(this.prefixes) == 0 && len(this.suffixes) == 0 {
+		return line
+	}
+
+	fields := make([]string, 0, len(this.prefixes)+len(this.suffixes)+1)
+	fields = append(fields, this.prefixes...)
+	fields = appendNonEmpty(fields, line)
+	fields = append(fields, this.suffixes...)
+	return strings.Join(fields, " ")
+}
+
+func appendNonEmpty(fields []string, field string) []string {
+	if field == "" {
+		return fields
+	}
+	return append(fields, field)
+}
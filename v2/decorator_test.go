@@ -0,0 +1,53 @@
+package pbar
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/smarty/assertions/should"
+	"github.com/smarty/gunit"
+)
+
+func TestDecoratorFixture(t *testing.T) {
+	gunit.Run(new(DecoratorFixture), t)
+}
+
+type DecoratorFixture struct {
+	*gunit.Fixture
+}
+
+func (this *DecoratorFixture) TestDecoratorsSurroundTheLine() {
+	var received Snapshot
+	file := DecoratorFunc(func(Snapshot) string { return "file.csv" })
+	empty := DecoratorFunc(func(Snapshot) string { return "" })
+	remaining := DecoratorFunc(func(snapshot Snapshot) string {
+		received = snapshot
+		return fmt.Sprintf("%d left", snapshot.Target-snapshot.Current)
+	})
+
+	start := time.Now()
+	progressBar := NewPBar(uint16(10), BarLength(2),
+		PrependDecorators(file, empty), AppendDecorators(remaining))
+	progressBar.now = func() time.Time { return start }
+	progressBar.initializeBar()
+	progressBar.Update(4)
+	progressBar.updateBar()
+
+	this.So(progressBar.line(), should.Equal, "file.csv [  ] (4/10) 40% 6 left")
+	this.So(received, should.Resemble, Snapshot{Current: 4, Target: 10, Width: 2})
+}
+
+func (this *DecoratorFixture) TestDecoratorsAreCalledOncePerFrame() {
+	calls := 0
+	counter := DecoratorFunc(func(Snapshot) string { calls++; return fmt.Sprintf("call %d", calls) })
+	progressBar := NewPBar(10, AutoWidth(), AppendDecorators(counter))
+	progressBar.resize(30)
+	progressBar.initializeBar()
+	progressBar.Update(5)
+	progressBar.updateBar()
+
+	this.So(progressBar.lastFrame(), should.Equal, "[====     ] (5/10) 50% call 2")
+	this.So(progressBar.lastFrame(), should.Equal, "[====     ] (5/10) 50% call 2")
+	this.So(calls, should.Equal, 2)
+}
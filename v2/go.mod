@@ -6,6 +6,5 @@ require (
 	github.com/pkg/term v1.1.0
 	github.com/smarty/assertions v1.15.1
 	github.com/smarty/gunit v1.5.0
+	golang.org/x/sys v0.37.0
 )
-
-require golang.org/x/sys v0.37.0 // indirect
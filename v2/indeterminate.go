@@ -0,0 +1,39 @@
+package pbar
+
+// paintIndeterminate draws a segment that bounces between the ends of the bar,
+// moving one cell per frame, and advances the spinner.
+func (this *PBarSupport) paintIndeterminate() {
+	segment := max(1, this.barLength/5)
+	travel := this.barLength - segment
+
+	position := 0
+	if travel > 0 {
+		position = this.frames % (2 * travel)
+		if position > travel {
+			position = 2*travel - position
+		}
+	}
+
+	this.filledFrom, this.filledTo = position+1, position+segment
+
+	if len(this.spinner) > 0 {
+		this.spinnerFrame = this.spinner[this.frames%len(this.spinner)]
+	}
+
+	for i := 1; i <= this.barLength; i++ {
+		if i > position && i <= position+segment {
+			this.barVisual[i] = this.barCompleted
+		} else {
+			this.barVisual[i] = this.barUncompleted
+		}
+	}
+}
+
+// bar renders the {bar} field: the spinner, when one is configured and the target
+// is unknown, or else the bar itself.
+func (this *PBarSupport) bar() string {
+	if this.indeterminate && len(this.spinner) > 0 {
+		return string(this.spinnerFrame)
+	}
+	return this.colorBar()
+}
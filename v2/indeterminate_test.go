@@ -0,0 +1,74 @@
+package pbar
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/smarty/assertions/should"
+	"github.com/smarty/gunit"
+)
+
+func TestIndeterminateFixture(t *testing.T) {
+	gunit.Run(new(IndeterminateFixture), t)
+}
+
+type IndeterminateFixture struct {
+	*gunit.Fixture
+	now time.Time
+}
+
+func (this *IndeterminateFixture) Setup() {
+	this.now = time.Now()
+}
+
+func (this *IndeterminateFixture) clock() time.Time { return this.now }
+
+func (this *IndeterminateFixture) TestSegmentBounces() {
+	progressBar := NewPBar(0, BarLength(5), BarCompleted('#'), BarUncompleted('.'))
+	progressBar.initializeBar()
+
+	var frames []string
+	for range 9 {
+		progressBar.updateBar()
+		frames = append(frames, string(progressBar.barVisual))
+	}
+
+	this.So(frames, should.Resemble, []string{
+		"[.#...]", "[..#..]", "[...#.]", "[....#]", "[...#.]", "[..#..]", "[.#...]", "[#....]", "[.#...]",
+	})
+}
+
+func (this *IndeterminateFixture) TestLineShowsCountAndRate() {
+	progressBar := NewPBar(0, BarLength(5), BarSpinner("|/-\\"), ShowRate("rows"))
+	progressBar.now = this.clock
+	progressBar.initializeBar()
+
+	this.now = this.now.Add(time.Second)
+	progressBar.Update(1500)
+	progressBar.updateBar()
+
+	this.So(progressBar.line(), should.Equal, "/ (1,500) 1,500 rows/s")
+}
+
+func (this *IndeterminateFixture) TestSetTargetSwitchesToDeterminate() {
+	progressBar := NewPBar(0, BarLength(4))
+	progressBar.initializeBar()
+	progressBar.Update(50)
+	progressBar.updateBar()
+	this.So(progressBar.indeterminate, should.BeTrue)
+
+	progressBar.SetTarget(100)
+	progressBar.updateBar()
+
+	this.So(progressBar.line(), should.Equal, "[==  ] (50/100) 50%")
+}
+
+func (this *IndeterminateFixture) TestFinishFixesTheTotal() {
+	progressBar := NewPBar(0, BarLength(4), OutputWriter(io.Discard), RefreshIntervalMilliseconds(1))
+	progressBar.Start()
+	progressBar.Update(42)
+	progressBar.Finish()
+
+	this.So(progressBar.line(), should.Equal, "[====] (42/42) 100%")
+}
@@ -0,0 +1,50 @@
+package pbar
+
+import "strings"
+
+// Alignment positions the label within the column set by LabelWidth.
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignRight
+)
+
+// Truncation chooses which part of a label too long for its column is replaced with an ellipsis.
+type Truncation int
+
+const (
+	TruncateEnd    Truncation = iota // /var/log/serv…
+	TruncateStart                    // …/service.log
+	TruncateMiddle                   // /var/l…ce.log
+)
+
+// SetLabel replaces the label, e.g. with the name of the file being processed.
+// [locks mutex]
+func (this *PBar[T]) SetLabel(label string) {
+	this.mutex.Lock()
+	this.barLabel = label
+	this.mutex.Unlock()
+
+	this.markDirty(this.current())
+}
+
+// label renders the label: truncated and padded to the column set by LabelWidth,
+// then truncated further when the line does not fit in the terminal.
+func (this *PBarSupport) label() string {
+	label := this.barLabel
+	if this.labelWidth > 0 {
+		label = truncateWidth(label, this.labelWidth, this.labelTruncation)
+		padding := strings.Repeat(" ", this.labelWidth-displayWidth(label))
+		if this.labelAlignment == AlignRight {
+			label = padding + label
+		} else {
+			label += padding
+		}
+	}
+
+	if this.labelOverflow > 0 {
+		label = truncateWidth(label, displayWidth(label)-this.labelOverflow, this.labelTruncation)
+	}
+	return label
+}
@@ -0,0 +1,86 @@
+package pbar
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/smarty/assertions/should"
+	"github.com/smarty/gunit"
+)
+
+func TestLabelFixture(t *testing.T) {
+	gunit.Run(new(LabelFixture), t)
+}
+
+type LabelFixture struct {
+	*gunit.Fixture
+}
+
+func (this *LabelFixture) label(label string, options ...Option) string {
+	progressBar := NewPBar(100, append([]Option{BarLabel(label)}, options...)...)
+	progressBar.initializeBar()
+	return progressBar.label()
+}
+
+func (this *LabelFixture) TestLabelsKeepTheirOwnWidthByDefault() {
+	this.So(this.label("a.txt"), should.Equal, "a.txt")
+}
+
+func (this *LabelFixture) TestFixedWidthLabelsArePadded() {
+	this.So(this.label("a.txt", LabelWidth(8, AlignLeft)), should.Equal, "a.txt   ")
+	this.So(this.label("a.txt", LabelWidth(8, AlignRight)), should.Equal, "   a.txt")
+	this.So(this.label("日本", LabelWidth(6, AlignRight)), should.Equal, "  日本")
+}
+
+func (this *LabelFixture) TestFixedWidthLabelsAreTruncated() {
+	const path = "/var/log/service.log"
+	this.So(this.label(path, LabelWidth(13, AlignLeft)), should.Equal, "/var/log/ser…")
+	this.So(this.label(path, LabelWidth(13, AlignLeft), LabelTruncation(TruncateStart)), should.Equal, "…/service.log")
+	this.So(this.label(path, LabelWidth(13, AlignLeft), LabelTruncation(TruncateMiddle)), should.Equal, "/var/l…ce.log")
+}
+
+func (this *LabelFixture) TestStackedBarsLineUp() {
+	container := NewContainer(OutputWriter(io.Discard))
+	short := NewPBar(10, BarLength(4), BarLabel("a "), LabelWidth(6, AlignLeft))
+	long := NewPBar(10, BarLength(4), BarLabel("longer name "), LabelWidth(6, AlignLeft))
+	container.Add(short)
+	container.Add(long)
+
+	this.So(short.frame(), should.Equal, "a     [    ] (0/10) 0%")
+	this.So(long.frame(), should.Equal, "longe…[    ] (0/10) 0%")
+}
+
+func (this *LabelFixture) TestSetLabel() {
+	progressBar := NewPBar(10, BarLength(4), BarLabel("first "))
+	progressBar.initializeBar()
+	progressBar.dirty.Store(false)
+
+	progressBar.SetLabel("second ")
+
+	this.So(progressBar.changed(), should.BeTrue)
+	this.So(progressBar.frame(), should.Equal, "second [    ] (0/10) 0%")
+}
+
+func (this *LabelFixture) TestSetLabelWhileRunning() {
+	output := new(bytes.Buffer)
+	progressBar := NewPBar(1000, OutputWriter(output), RefreshIntervalMilliseconds(1))
+	progressBar.Start()
+
+	var waiter sync.WaitGroup
+	for worker := range 4 {
+		waiter.Add(1)
+		go func() {
+			defer waiter.Done()
+			for i := range 100 {
+				progressBar.SetLabel(string(rune('a'+worker)) + string(rune('0'+i%10)) + " ")
+				progressBar.Increment()
+			}
+		}()
+	}
+	waiter.Wait()
+	progressBar.Finish()
+
+	this.So(output.String(), should.EndWith, "(1,000/1,000) 100% ")
+}
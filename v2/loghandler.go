@@ -0,0 +1,124 @@
+package pbar
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Display is implemented by *PBar[T] and *Container, the targets of a LogHandler.
+type Display interface {
+	printAround(print func())
+	progress() (current, target int64)
+}
+
+// LogHandler is a slog.Handler that forwards records to an inner handler, clearing the bars of
+// its Display while the inner handler writes so that records appear above the bars rather than
+// over them. The inner handler should write to the terminal directly, not through Writer or Bypass.
+type LogHandler struct {
+	inner    slog.Handler
+	display  Display
+	attached bool // add the progress of the display to each record
+
+	// The progress group belongs at the top level of a record even after WithGroup,
+	// so the handler as it was before the first group is kept along with the calls
+	// that followed, which are replayed around the progress group of each record.
+	outer  slog.Handler
+	scoped []func(slog.Handler) slog.Handler
+}
+
+// LogHandlerOption configures a LogHandler.
+type LogHandlerOption func(*LogHandler)
+
+// LogProgress adds the progress of the display to each record as a "progress" group
+// of current, target and percent. The percent is omitted while the target is unknown.
+// The group stays at the top level of the record when the logger has groups of its own.
+func LogProgress() LogHandlerOption {
+	return func(h *LogHandler) { h.attached = true }
+}
+
+// NewLogHandler wraps inner, e.g. slog.New(pbar.NewLogHandler(slog.NewTextHandler(os.Stderr, nil), container)).
+func NewLogHandler(inner slog.Handler, display Display, options ...LogHandlerOption) *LogHandler {
+	this := &LogHandler{inner: inner, display: display, outer: inner}
+	for _, configure := range options {
+		configure(this)
+	}
+	return this
+}
+
+func (this *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return this.inner.Enabled(ctx, level)
+}
+
+func (this *LogHandler) Handle(ctx context.Context, record slog.Record) (err error) {
+	inner := this.inner
+	if this.attached && len(this.scoped) == 0 {
+		record = record.Clone()
+		record.AddAttrs(this.progressAttr())
+	} else if this.attached {
+		inner = this.outer.WithAttrs([]slog.Attr{this.progressAttr()})
+		for _, scope := range this.scoped {
+			inner = scope(inner)
+		}
+	}
+	this.display.printAround(func() { err = inner.Handle(ctx, record) })
+	return err
+}
+
+func (this *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(this.scoped) == 0 {
+		return this.with(this.inner.WithAttrs(attrs), nil)
+	}
+	return this.with(this.inner.WithAttrs(attrs), func(h slog.Handler) slog.Handler { return h.WithAttrs(attrs) })
+}
+
+func (this *LogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return this
+	}
+	return this.with(this.inner.WithGroup(name), func(h slog.Handler) slog.Handler { return h.WithGroup(name) })
+}
+
+// with derives a handler that writes to inner, recording scope for replay once a group has been opened.
+func (this *LogHandler) with(inner slog.Handler, scope func(slog.Handler) slog.Handler) *LogHandler {
+	derived := &LogHandler{inner: inner, display: this.display, attached: this.attached, outer: this.outer}
+	if scope == nil {
+		derived.outer = inner
+		return derived
+	}
+	derived.scoped = append(append([]func(slog.Handler) slog.Handler{}, this.scoped...), scope)
+	return derived
+}
+
+func (this *LogHandler) progressAttr() slog.Attr {
+	current, target := this.display.progress()
+	if target == 0 {
+		return slog.Group("progress", "current", current, "target", target)
+	}
+	return slog.Group("progress", "current", current, "target", target, "percent", current*100/target)
+}
+
+// progress reports the count and target of the bar.
+// [locks mutex]
+func (this *PBar[T]) progress() (current, target int64) {
+	return int64(this.current()), int64(this.Target())
+}
+
+// progress reports the combined count and target of the Container's bars. The target
+// is unknown (zero) while any bar's target is unknown.
+// [locks mutex]
+func (this *Container) progress() (current, target int64) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	unknown := false
+	for _, bar := range this.bars {
+		barCurrent, barTarget := bar.progress()
+		current += barCurrent
+		target += barTarget
+		unknown = unknown || barTarget == 0
+	}
+	if unknown {
+		return current, 0
+	}
+	return current, target
+}
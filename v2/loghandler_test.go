@@ -0,0 +1,128 @@
+package pbar
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/smarty/assertions/should"
+	"github.com/smarty/gunit"
+)
+
+func TestLogHandlerFixture(t *testing.T) {
+	gunit.Run(new(LogHandlerFixture), t)
+}
+
+type LogHandlerFixture struct {
+	*gunit.Fixture
+	output *bytes.Buffer
+	inner  slog.Handler
+}
+
+func (this *LogHandlerFixture) Setup() {
+	this.output = new(bytes.Buffer)
+	this.inner = slog.NewTextHandler(this.output, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})
+}
+
+func (this *LogHandlerFixture) TestRecordsArePrintedAboveTheBar() {
+	progressBar := NewPBar(10, BarLength(4), OutputWriter(this.output))
+	progressBar.initializeBar()
+	progressBar.running = true
+	logger := slog.New(NewLogHandler(this.inner, progressBar))
+
+	logger.Info("hello", "file", "a.txt")
+
+	this.So(this.output.String(), should.Equal, "\r\x1b[2Klevel=INFO msg=hello file=a.txt\n[    ] (0/10) 0% ")
+}
+
+func (this *LogHandlerFixture) TestRecordsArePrintedAboveTheContainer() {
+	container := NewContainer(OutputWriter(this.output))
+	container.Add(NewPBar(10, BarLabel("one "), BarLength(4)))
+	container.running = true
+	container.repaint()
+	this.output.Reset()
+	logger := slog.New(NewLogHandler(this.inner, container))
+
+	logger.Warn("careful")
+
+	this.So(this.output.String(), should.Equal, ""+
+		"\x1b[1A\r\x1b[Jlevel=WARN msg=careful\n"+
+		"\r\x1b[2Kone [    ] (0/10) 0%\n")
+}
+
+func (this *LogHandlerFixture) TestProgressAttributes() {
+	progressBar := NewPBar(200, OutputWriter(this.output))
+	progressBar.Update(50)
+	logger := slog.New(NewLogHandler(this.inner, progressBar, LogProgress()))
+
+	logger.Info("hello")
+
+	this.So(this.output.String(), should.Equal, "level=INFO msg=hello progress.current=50 progress.target=200 progress.percent=25\n")
+}
+
+func (this *LogHandlerFixture) TestContainerProgressIsCombined() {
+	container := NewContainer(OutputWriter(this.output))
+	first, second := NewPBar(100), NewPBar(uint8(100))
+	container.Add(first)
+	container.Add(second)
+	first.Update(100)
+	second.Update(50)
+	logger := slog.New(NewLogHandler(this.inner, container, LogProgress()))
+
+	logger.Info("hello")
+	container.Add(NewPBar(0))
+	logger.Info("unknown")
+
+	this.So(this.output.String(), should.Equal, ""+
+		"level=INFO msg=hello progress.current=150 progress.target=200 progress.percent=75\n"+
+		"level=INFO msg=unknown progress.current=150 progress.target=0\n")
+}
+
+func (this *LogHandlerFixture) TestAttributesAndGroupsReachTheInnerHandler() {
+	progressBar := NewPBar(10, OutputWriter(this.output))
+	handler := NewLogHandler(this.inner, progressBar).WithAttrs([]slog.Attr{slog.String("job", "import")}).WithGroup("request")
+	logger := slog.New(handler)
+
+	logger.Debug("hidden")
+	logger.Error("failed", "id", 7)
+
+	this.So(this.output.String(), should.Equal, "level=ERROR msg=failed job=import request.id=7\n")
+}
+
+func (this *LogHandlerFixture) TestProgressStaysOutsideOfGroups() {
+	progressBar := NewPBar(200, OutputWriter(this.output))
+	progressBar.Update(50)
+	handler := NewLogHandler(this.inner, progressBar, LogProgress()).
+		WithAttrs([]slog.Attr{slog.String("job", "import")}).
+		WithGroup("request").
+		WithAttrs([]slog.Attr{slog.Int("id", 7)})
+	logger := slog.New(handler)
+
+	logger.Info("hello", "path", "/")
+
+	this.So(this.output.String(), should.Equal, "level=INFO msg=hello job=import "+
+		"progress.current=50 progress.target=200 progress.percent=25 request.id=7 request.path=/\n")
+}
+
+func (this *LogHandlerFixture) TestRecordsOfContainedBarsArePrintedAboveTheContainer() {
+	container := NewContainer(OutputWriter(this.output))
+	progressBar := NewPBar(10, BarLength(4))
+	container.Add(progressBar)
+	container.running = true
+	container.repaint()
+	this.output.Reset()
+	logger := slog.New(NewLogHandler(this.inner, progressBar))
+
+	logger.Warn("careful")
+
+	this.So(this.output.String(), should.Equal, ""+
+		"\x1b[1A\r\x1b[Jlevel=WARN msg=careful\n"+
+		"\r\x1b[2K[    ] (0/10) 0%\n")
+}
@@ -1,7 +1,9 @@
 package pbar
 
 import (
+	"cmp"
 	"io"
+	"slices"
 	"time"
 )
 
@@ -9,6 +11,8 @@ import (
 // Each one allows configuration of the PBar.
 type Option func(*PBarSupport)
 
+// RefreshIntervalMilliseconds sets the minimum interval between frames. Changes are painted
+// as they happen, subject to this limit, and completion is always painted immediately.
 func RefreshIntervalMilliseconds(interval int) Option {
 	return func(c *PBarSupport) { c.refreshInterval = time.Duration(interval) * time.Millisecond }
 }
@@ -17,6 +21,12 @@ func BarLength(length int) Option {
 	return func(c *PBarSupport) { c.barLength = length }
 }
 
+// AutoWidth sizes the bar to fill the terminal width left over by the label, summary and
+// decorators, and resizes it when the terminal is resized. BarLength is ignored.
+func AutoWidth() Option {
+	return func(c *PBarSupport) { c.autoWidth = true }
+}
+
 func BarLeft(left rune) Option {
 	return func(c *PBarSupport) { c.barLeft = left }
 }
@@ -33,13 +43,203 @@ func BarCompleted(completed rune) Option {
 	return func(c *PBarSupport) { c.barCompleted = completed }
 }
 
+// BarHead draws head over the leading completed cells, e.g. BarCompleted('=') with BarHead(">")
+// for [=====>    ]. The head may span several cells, including double-width runes.
+func BarHead(head string) Option {
+	return func(c *PBarSupport) { c.barHead = head }
+}
+
+// BarFullHead replaces the head once the bar reaches 100%, e.g. BarFullHead("=") to hide it.
+func BarFullHead(head string) Option {
+	return func(c *PBarSupport) { c.barFullHead = head }
+}
+
+// BarSmooth renders the boundary cell of the bar with Unicode partial blocks (▏▎▍▌▋▊▉█),
+// moving the bar in eighths of a cell. Completed cells are drawn as '█' and BarCompleted is ignored.
+func BarSmooth() Option {
+	return func(c *PBarSupport) { c.partials = smoothBlocks }
+}
+
+// BarSpinner replaces the bouncing bar shown while the target is unknown (zero)
+// with the given frames, one rune per frame, e.g. BarSpinner("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏").
+func BarSpinner(frames string) Option {
+	return func(c *PBarSupport) { c.spinner = []rune(frames) }
+}
+
+// BarAbortedCompleted sets the rune used to redraw the completed cells after Abort.
+// By default the completed cells are left unchanged.
+func BarAbortedCompleted(completed rune) Option {
+	return func(c *PBarSupport) { c.barAbortedCompleted = completed }
+}
+
+// BarFailedCompleted sets the rune used to redraw the completed cells after Fail.
+// By default the completed cells are left unchanged.
+func BarFailedCompleted(completed rune) Option {
+	return func(c *PBarSupport) { c.barFailedCompleted = completed }
+}
+
+// ClearOnAbort erases the bar's line after Abort instead of painting a final frame.
+func ClearOnAbort() Option {
+	return func(c *PBarSupport) { c.clearOnAbort = true }
+}
+
+// BarCompletedColor colors the completed cells of the bar. Colors are only written to terminals
+// and are disabled when the NO_COLOR environment variable is set; see ForceColor.
+func BarCompletedColor(color Color) Option {
+	return func(c *PBarSupport) { c.colors.completed = color }
+}
+
+// BarCompletedColorThresholds changes the color of the completed cells as the bar progresses.
+// Each threshold's color applies from its percent on, e.g. Red from 0, Yellow from 33 and Green from 66.
+func BarCompletedColorThresholds(thresholds ...ColorThreshold) Option {
+	sorted := slices.Clone(thresholds)
+	slices.SortStableFunc(sorted, func(a, b ColorThreshold) int { return cmp.Compare(a.Percent, b.Percent) })
+	return func(c *PBarSupport) { c.colors.thresholds = sorted }
+}
+
+// BarCompletedGradient colors each completed cell with a truecolor blend of start and end
+// according to its position across the bar, from start at the first cell to end at the last.
+func BarCompletedGradient(start, end RGB) Option {
+	return func(c *PBarSupport) { c.colors.gradient = []RGB{start, end} }
+}
+
+// BarUncompletedColor colors the uncompleted cells of the bar.
+func BarUncompletedColor(color Color) Option {
+	return func(c *PBarSupport) { c.colors.uncompleted = color }
+}
+
+// BarBracketColor colors the left and right markers of the bar.
+func BarBracketColor(color Color) Option {
+	return func(c *PBarSupport) { c.colors.brackets = color }
+}
+
+// BarLabelColor colors the label.
+func BarLabelColor(color Color) Option {
+	return func(c *PBarSupport) { c.colors.label = color }
+}
+
+// BarPercentColor colors the percent, or the outcome that replaces it.
+func BarPercentColor(color Color) Option {
+	return func(c *PBarSupport) { c.colors.percent = color }
+}
+
+// ForceColor writes colors even when the output is not a terminal or NO_COLOR is set.
+func ForceColor() Option {
+	return func(c *PBarSupport) { c.colors.force = true }
+}
+
 func BarLabel(label string) Option {
 	return func(c *PBarSupport) { c.barLabel = label }
 }
 
-func OutputWriter(writer io.Writer) Option {
+// LabelWidth renders the label in a column of the given number of terminal cells, padding
+// shorter labels and truncating longer ones, so that the bars of a Container line up.
+func LabelWidth(width int, align Alignment) Option {
+	return func(c *PBarSupport) {
+		c.labelWidth = width
+		c.labelAlignment = align
+	}
+}
+
+// LabelTruncation chooses where a label too long for its column, or for the terminal, is cut.
+// Cutting the start keeps the end of file paths visible. Default TruncateEnd.
+func LabelTruncation(truncation Truncation) Option {
+	return func(c *PBarSupport) { c.labelTruncation = truncation }
+}
+
+// ShowElapsed adds the time since the bar started to the summary text.
+func ShowElapsed() Option {
+	return func(c *PBarSupport) { c.showElapsed = true }
+}
+
+// ShowRemaining adds the estimated time remaining to the summary text.
+func ShowRemaining() Option {
+	return func(c *PBarSupport) { c.showRemaining = true }
+}
+
+// ShowFinishTime adds the projected wall-clock finish time to the summary text.
+func ShowFinishTime() Option {
+	return func(c *PBarSupport) { c.showFinishTime = true }
+}
+
+// ShowRate adds the observed throughput to the summary text, e.g. "5,120 records/s".
+func ShowRate(unit string) Option {
+	return func(c *PBarSupport) {
+		c.showRate = true
+		c.rateBytes = false
+		c.rateUnit = unit
+	}
+}
+
+// ShowByteRate adds the observed throughput to the summary text, treating each
+// counted item as a byte and scaling to KiB, MiB, etc., e.g. "1.5 MiB/s".
+func ShowByteRate() Option {
 	return func(c *PBarSupport) {
-		c.testing = true
-		c.output = writer
+		c.showRate = true
+		c.rateBytes = true
 	}
 }
+
+// RateSmoothing sets the weight (0 < smoothing <= 1) given to the newest rate sample
+// when estimating throughput and time remaining. Lower values produce steadier estimates. Default 0.2.
+func RateSmoothing(smoothing float64) Option {
+	return func(c *PBarSupport) { c.rate.smoothing = smoothing }
+}
+
+// Template sets the layout of the rendered line using {field} tokens. The fields are
+// {label}, {bar}, {counts}, {percent}, {rate}, {elapsed}, {eta}, {finish} and {stats}
+// (the statistics enabled by the Show* options); literal braces are written as {{ and }}.
+// While the target is unknown {percent} renders as "" and {stats} always includes the rate.
+// Template panics if the layout cannot be parsed; see ParseTemplate. Default TemplateDefault.
+func Template(text string) Option {
+	option, err := ParseTemplate(text)
+	if err != nil {
+		panic(err)
+	}
+	return option
+}
+
+// ParseTemplate is like Template but returns an error for a layout that cannot be parsed.
+func ParseTemplate(text string) (Option, error) {
+	segments, err := parseTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+	return func(c *PBarSupport) { c.template = segments }, nil
+}
+
+// PrependDecorators renders each decorator, in order, before the templated line.
+func PrependDecorators(decorators ...Decorator) Option {
+	return func(c *PBarSupport) { c.prepended = append(c.prepended, decorators...) }
+}
+
+// AppendDecorators renders each decorator, in order, after the templated line.
+func AppendDecorators(decorators ...Decorator) Option {
+	return func(c *PBarSupport) { c.appended = append(c.appended, decorators...) }
+}
+
+// PlainOutput writes progress as newline-terminated lines, as when the output is not a
+// terminal, rather than repainting a single line in place. See PlainIntervalSeconds and PlainPercentStep.
+func PlainOutput() Option {
+	return func(c *PBarSupport) { c.plain = true }
+}
+
+// PlainIntervalSeconds sets how often a line is written in plain mode, in addition to
+// the lines for each percent step. Zero disables timed lines. Default 10 seconds.
+func PlainIntervalSeconds(interval int) Option {
+	return func(c *PBarSupport) { c.plainInterval = time.Duration(interval) * time.Second }
+}
+
+// PlainPercentStep writes a line in plain mode each time the bar completes another
+// percent step of the target. Zero disables these lines. Default 10.
+func PlainPercentStep(percent int) Option {
+	return func(c *PBarSupport) { c.plainStep = percent }
+}
+
+// OutputWriter sends the bar, and every escape sequence used to draw it, to writer instead
+// of os.Stdout, e.g. os.Stderr so that a program can pipe its data to stdout. Terminals are
+// repainted in place; files and pipes receive plain lines (see PlainOutput); other writers
+// are repainted in place without tracking the terminal width.
+func OutputWriter(writer io.Writer) Option {
+	return func(c *PBarSupport) { c.output = writer }
+}
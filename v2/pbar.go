@@ -3,12 +3,13 @@ package pbar
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
-	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/term"
@@ -23,28 +24,90 @@ const (
 	BarRightDefault        = ']'
 	BarUnCompletedDefault  = ' '
 	BarCompletedDefault    = '='
+	RateSmoothingDefault   = 0.2
+
+	PlainIntervalDefault    = 10 * time.Second
+	PlainPercentStepDefault = 10
+
+	OutcomeCancelled = "cancelled"
+	OutcomeAborted   = "aborted"
+	OutcomeFailed    = "failed"
 )
 
 type PBar[T integer] struct {
 	PBarSupport
-	mutex        sync.Mutex
-	currentCount T
-	TargetCount  T
+	mutex      sync.Mutex
+	counter    atomic.Uint64 // holds a T; wrapping arithmetic keeps negative deltas correct
+	finished   bool
+	running    bool
+	finalFrame bool                      // the latest frame was rendered after the bar was finished
+	dirty      atomic.Bool               // the state has changed since the last frame
+	goal       atomic.Uint64             // mirrors TargetCount so that updates can detect completion without locking
+	done       chan struct{}             // closed once the final frame has been painted
+	doneOnce   sync.Once                 // closes done
+	owner      atomic.Pointer[Container] // the Container that paints the bar, once added to one
+
+	// Deprecated: reading or writing TargetCount while the bar is running races with
+	// the render goroutine; use Target, SetTarget and AddTarget instead.
+	TargetCount T
 }
 
 type PBarSupport struct {
-	barVisual      []rune
-	barPercent     string
-	terminal       *term.Term
-	cursorPosition CursorPosition
-	tty            string
+	barVisual  []rune
+	barCounts  string
+	barPercent string
+	barOutcome string // replaces the percent once the bar is cancelled, aborted or failed
+	barEnded   rune   // replaces the completed rune once the bar is aborted or failed
+	hidden     bool
+	renderedAt time.Time
+	remaining  float64       // items left to count as of renderedAt
+	rows       int           // terminal rows taken by the previous frame, which the next frame returns to the start of
+	wake       chan struct{} // interrupts the render goroutine's wait between frames
+	tty        string
 
 	refreshInterval                                 time.Duration
 	barLength                                       int
 	barLeft, barRight, barUncompleted, barCompleted rune
 	barLabel                                        string
-	testing                                         bool
+	interactive                                     bool // the output is a terminal
 	output                                          io.Writer
+	contained                                       bool
+
+	showElapsed, showRemaining, showFinishTime bool
+	showRate, rateBytes                        bool
+	rateUnit                                   string
+	rate                                       rateEstimator
+	template                                   []templateSegment
+	prepended, appended                        []Decorator
+	prefixes, suffixes                         []string // the output of the decorators for the current frame
+	partials                                   []rune   // boundary cell fills, ending with a full cell; empty for whole cells only
+	barAbortedCompleted, barFailedCompleted    rune
+	clearOnAbort                               bool
+	timed                                      bool // the layout changes with time alone
+	autoWidth                                  bool
+	filledFrom, filledTo                       int     // the cells of barVisual painted as completed
+	barHead, barFullHead                       string  // drawn over the leading completed cells; see BarHead
+	head                                       string  // the head of the current frame
+	headFrom, headTo                           int     // the cells of barVisual covered by the head, or 0 for none
+	cellWidth                                  int     // terminal cells per cell of the bar, 2 when drawn with wide runes
+	labelOverflow                              int     // terminal cells cut from the label so that the line fits
+	fraction                                   float32 // the fraction of the target completed
+	colors                                     colors
+	columns, fittedColumns                     int  // terminal width, and the width the bar was last fitted to
+	indeterminate                              bool // the target is unknown (zero)
+	spinner                                    []rune
+	spinnerFrame                               rune
+	frames                                     int // frames rendered, which animates indeterminate bars
+	snapshot                                   Snapshot
+	now                                        func() time.Time
+	labelWidth                                 int        // the width of the label column, or 0 for the label's own width
+	labelAlignment                             Alignment  // see LabelWidth
+	labelTruncation                            Truncation // see LabelTruncation
+
+	plain         bool          // write a line per update instead of repainting in place
+	plainInterval time.Duration // see PlainIntervalSeconds
+	plainStep     int           // see PlainPercentStep
+	logged        plainLog
 }
 
 func DefaultPBarSupport() PBarSupport {
@@ -57,6 +120,12 @@ func DefaultPBarSupport() PBarSupport {
 		barCompleted:    BarCompletedDefault,
 		tty:             TTY,
 		output:          os.Stdout,
+		rate:            rateEstimator{smoothing: RateSmoothingDefault},
+		now:             time.Now,
+		template:        mustParseTemplate(TemplateDefault),
+		wake:            make(chan struct{}, 1),
+		plainInterval:   PlainIntervalDefault,
+		plainStep:       PlainPercentStepDefault,
 	}
 }
 
@@ -65,11 +134,6 @@ type integer interface {
 		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
 }
 
-type CursorPosition struct {
-	row int8
-	col int8
-}
-
 func NewPBar[T integer](targetCount T, options ...Option) *PBar[T] {
 	return new(PBar[T]).configure(targetCount, options)
 }
@@ -80,148 +144,579 @@ func (this *PBar[T]) configure(targetCount T, options []Option) *PBar[T] {
 	defer this.mutex.Unlock()
 
 	this.TargetCount = targetCount
+	this.goal.Store(uint64(targetCount))
 	this.PBarSupport = DefaultPBarSupport()
+	this.done = make(chan struct{})
 
 	for _, configure := range options {
 		configure(&this.PBarSupport)
 	}
+	this.timed = this.timeDependent()
+	this.colors.enable(this.output, os.Getenv("NO_COLOR"))
 
 	return this
 }
 
 func (this *PBar[T]) Start() {
+	this.StartContext(context.Background())
+}
+
+// StartContext is like Start, but cancelling ctx stops the render goroutine
+// after painting a final frame marked as cancelled.
+// [locks mutex]
+func (this *PBar[T]) StartContext(ctx context.Context) {
+	this.mutex.Lock()
+	if this.contained || this.running {
+		this.mutex.Unlock()
+		return // the Container owns rendering, or the bar is already running
+	}
+	this.running = true
+	this.mutex.Unlock()
+
 	var waiter sync.WaitGroup
 	waiter.Add(1)
-	go this.start(&waiter)
+	go this.start(ctx, &waiter)
 	waiter.Wait()
 }
 
 // [locks mutex]
-func (this *PBar[T]) start(waiter *sync.WaitGroup) {
-	this.saveCursorPosition()
-	this.initializeBar()
+func (this *PBar[T]) start(ctx context.Context, waiter *sync.WaitGroup) {
+	defer this.settle(true)
+
+	this.prepare()
+	resized, stopWatching := this.watchWidth()
+	defer stopWatching()
 	waiter.Done()
 
 	for {
 		this.updateBar()
 		this.repaint()
-		this.mutex.Lock()
-		done := this.currentCount == this.TargetCount
-		this.mutex.Unlock()
+		painted := time.Now()
 
-		if done {
+		if this.complete() {
 			break
 		}
-		time.Sleep(this.refreshInterval)
+		if !this.await(ctx, nil, painted, resized, this) {
+			this.cancel()
+			this.updateBar()
+			this.repaint()
+			return
+		}
 	}
 }
 
+// pacer is implemented by *PBar[T] and *Container, whose render goroutines await their frames.
+type pacer interface {
+	animated() bool
+	ended() bool
+	measureWidth()
+}
+
+// await blocks until the next frame of source is due, reporting false if ctx is cancelled or
+// stop is closed first. A frame is due once the state changes or the terminal is resized (or,
+// for layouts that change with time alone, once the refresh interval passes), but no sooner
+// than the refresh interval after the previous frame, unless a bar has ended, which is painted immediately.
+func (this *PBarSupport) await(ctx context.Context, stop <-chan struct{}, painted time.Time, resized <-chan os.Signal, source pacer) bool {
+	var due <-chan time.Time
+	if source.animated() {
+		due = time.After(time.Until(painted.Add(this.refreshInterval)))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-stop:
+			return false
+		case <-due:
+			return true
+		case <-resized:
+			source.measureWidth()
+			return true
+		case <-this.wake:
+		}
+
+		if source.ended() {
+			return true
+		}
+		throttle := time.Until(painted.Add(this.refreshInterval))
+		if throttle <= 0 {
+			return true
+		}
+		due = time.After(throttle)
+	}
+}
+
+// complete reports whether the bar has been finished, aborted, failed or cancelled, which
+// ends the render loop. A bar that reaches its target keeps rendering until then, since
+// the target may still grow, e.g. as a paging API or directory walk finds more work.
 // [locks mutex]
-func (this *PBar[T]) Finish() {
+func (this *PBar[T]) complete() bool {
 	this.mutex.Lock()
-	this.currentCount = this.TargetCount
-	this.mutex.Unlock()
-	time.Sleep(this.refreshInterval)
-	this.updateBar()
-	this.repaint()
+	defer this.mutex.Unlock()
+
+	return this.finished
 }
 
 // [locks mutex]
-func (this *PBar[T]) Update(current T) {
+func (this *PBar[T]) ended() bool {
 	this.mutex.Lock()
 	defer this.mutex.Unlock()
 
-	this.currentCount = current
+	return this.finished || reached(this.current(), this.TargetCount)
+}
+
+// reached reports whether current has reached (or passed) a known target, which may
+// have shrunk below the count or been overshot by Add.
+func reached[T integer](current, target T) bool {
+	return target != 0 && current >= target
 }
 
 // [locks mutex]
-func (this *PBar[T]) updateBar() {
+func (this *PBar[T]) animated() bool {
 	this.mutex.Lock()
 	defer this.mutex.Unlock()
 
-	percentCompleted := float32(this.currentCount) / float32(this.TargetCount)
-	completed := int(percentCompleted * float32(this.barLength))
+	return this.timed || this.indeterminate
+}
 
-	for i := 1; i <= this.barLength; i++ {
-		if i <= completed {
-			this.barVisual[i] = this.barCompleted
-		} else {
-			this.barVisual[i] = this.barUncompleted
-		}
+// changed reports whether the next frame may differ from the previous one.
+func (this *PBar[T]) changed() bool {
+	return this.dirty.Load() || this.animated()
+}
+
+// markDirty flags a change to the state and wakes the render goroutine, which
+// repaints subject to the refresh interval. Reaching the target always wakes it,
+// so that completion is painted immediately.
+func (this *PBar[T]) markDirty(current T) {
+	if !this.dirty.Load() && this.dirty.CompareAndSwap(false, true) {
+		this.wakeUp()
+	} else if reached(current, T(this.goal.Load())) {
+		this.wakeUp()
 	}
+}
+
+// Done returns a channel that is closed once the final frame of the bar has been painted:
+// by the render goroutine launched by Start, which has then released the terminal; by the
+// Container the bar was added to; or by Finish, Abort or Fail for a bar that was never started.
+func (this *PBar[T]) Done() <-chan struct{} {
+	return this.done
+}
 
-	this.barPercent = fmt.Sprintf("(%s/%s) %d%%",
-		comma(this.currentCount), comma(this.TargetCount), int(percentCompleted*100.0))
+// Wait blocks until the final frame of the bar has been painted (see Done). Wait returns
+// immediately if neither the bar nor its Container is rendering it.
+func (this *PBar[T]) Wait() {
+	if this.rendering() {
+		<-this.done
+	}
 }
 
+// rendering reports whether a render goroutine, the bar's own or its Container's, is yet to paint the final frame.
 // [locks mutex]
-func (this *PBar[T]) repaint() {
-	this.restoreCursorPosition()
+func (this *PBar[T]) rendering() bool {
+	if owner := this.owner.Load(); owner != nil {
+		return owner.painting()
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.running
+}
+
+// settle closes Done once the final frame has been painted, which is the frame rendered
+// after the bar was finished, or any frame painted as final.
+// [locks mutex]
+func (this *PBar[T]) settle(final bool) {
 	this.mutex.Lock()
-	// go to beginning of the line and print data
-	_, _ = fmt.Fprintf(this.output, "%c%s%s %s%c", 13, this.barLabel, string(this.barVisual), this.barPercent, 32)
+	final = final || this.finalFrame
 	this.mutex.Unlock()
+
+	if final {
+		this.doneOnce.Do(func() { close(this.done) })
+	}
 }
 
+// settling reports whether the bar has been finished, aborted, failed or cancelled
+// but has yet to render the frame that shows it.
 // [locks mutex]
-func (this *PBar[T]) openTty() (err error) {
+func (this *PBar[T]) settling() bool {
 	this.mutex.Lock()
 	defer this.mutex.Unlock()
-	this.terminal, err = term.Open(this.tty)
+
+	return this.finished && !this.finalFrame
+}
+
+// wakeUp interrupts the wait of the render goroutine that paints the bar: its own, or its Container's.
+func (this *PBar[T]) wakeUp() {
+	if owner := this.owner.Load(); owner != nil {
+		owner.wakeUp()
+	} else {
+		this.PBarSupport.wakeUp()
+	}
+}
+
+// wakeUp interrupts the render goroutine's wait so that it paints the next frame now.
+func (this *PBarSupport) wakeUp() {
+	select {
+	case this.wake <- struct{}{}:
+	default: // a frame is already pending
+	}
+}
+
+// cancel marks an unfinished bar as cancelled, which ends its render loop.
+func (this *PBar[T]) cancel() {
+	this.end(OutcomeCancelled, 0, false)
+}
+
+// Abort stops the bar where it is, without claiming completion, and paints a final
+// frame marked as aborted (or clears the line when the ClearOnAbort option is set).
+func (this *PBar[T]) Abort() {
+	if this.end(OutcomeAborted, this.barAbortedCompleted, this.clearOnAbort) {
+		this.paintFinal()
+	}
+}
+
+// Fail stops the bar where it is and paints a final frame showing err in place of the percent.
+func (this *PBar[T]) Fail(err error) {
+	outcome := OutcomeFailed
 	if err != nil {
-		this.testing = true // prevent attempts to save and restore cursor position
-		this.output = io.Discard
-		return
+		outcome += ": " + err.Error()
+	}
+	if this.end(outcome, this.barFailedCompleted, false) {
+		this.paintFinal()
+	}
+}
+
+// end records how an unfinished bar ended, reporting false if it had already ended.
+// [locks mutex]
+func (this *PBar[T]) end(outcome string, completed rune, hidden bool) bool {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.finished {
+		return false
+	}
+	this.finished = true
+	this.barOutcome = outcome
+	this.barEnded = completed
+	this.hidden = hidden
+	this.dirty.Store(true)
+	return true
+}
+
+// paintFinal has the render goroutine, the bar's own or its Container's, paint the final
+// frame and waits for it, or paints the frame directly when the bar was never started.
+// [locks mutex]
+func (this *PBar[T]) paintFinal() {
+	this.mutex.Lock()
+	contained, running, drawn := this.contained, this.running, len(this.barVisual) > 0
+	this.mutex.Unlock()
+
+	switch {
+	case contained, running:
+		this.wakeUp()
+		this.Wait()
+	case drawn:
+		this.updateBar()
+		this.repaint()
+		this.settle(false)
+	default:
+		this.prepare()
+		this.repaint()
+		this.settle(false)
+	}
+}
+
+// prepare decides how to render and renders the first frame of the bar.
+// [locks mutex]
+func (this *PBar[T]) prepare() {
+	this.mutex.Lock()
+	this.checkOutput()
+	this.mutex.Unlock()
+
+	this.initializeBar()
+}
+
+// Finish marks the bar complete and waits for the final frame to be painted.
+// [locks mutex]
+func (this *PBar[T]) Finish() {
+	this.mutex.Lock()
+	if this.barOutcome != "" {
+		this.mutex.Unlock()
+		return // the final frame has already been painted
+	}
+	if this.TargetCount == 0 {
+		this.TargetCount = this.current() // the total is now known
+		this.goal.Store(uint64(this.TargetCount))
 	}
-	_ = term.RawMode(this.terminal)
+	this.counter.Store(uint64(this.TargetCount))
+	this.finished = true
+	this.dirty.Store(true)
+	this.mutex.Unlock()
+
+	this.paintFinal()
+}
+
+// Update sets the current count. Update, Add and Increment are lock-free.
+func (this *PBar[T]) Update(current T) {
+	this.counter.Store(uint64(current))
+	this.markDirty(current)
+}
 
-	return
+// Add adds delta to the current count and may be called from many goroutines at once.
+func (this *PBar[T]) Add(delta T) {
+	this.markDirty(T(this.counter.Add(uint64(delta))))
 }
 
+// Increment adds one to the current count and may be called from many goroutines at once.
+func (this *PBar[T]) Increment() {
+	this.markDirty(T(this.counter.Add(1)))
+}
+
+func (this *PBar[T]) current() T {
+	return T(this.counter.Load())
+}
+
+// SetTarget replaces the target count. Setting a non-zero target on a bar that
+// started with an unknown (zero) target switches it to a determinate bar.
 // [locks mutex]
-func (this *PBar[T]) closeTty() {
+func (this *PBar[T]) SetTarget(target T) {
+	this.mutex.Lock()
+	this.TargetCount = target
+	this.goal.Store(uint64(target))
+	this.mutex.Unlock()
+
+	this.markDirty(this.current())
+}
+
+// AddTarget grows (or, with a negative delta, shrinks) the target count, for producers
+// that discover more work while the bar is running.
+// [locks mutex]
+func (this *PBar[T]) AddTarget(delta T) {
+	this.mutex.Lock()
+	this.TargetCount += delta
+	this.goal.Store(uint64(this.TargetCount))
+	this.mutex.Unlock()
+
+	this.markDirty(this.current())
+}
+
+// [locks mutex]
+func (this *PBar[T]) Target() T {
 	this.mutex.Lock()
 	defer this.mutex.Unlock()
-	_ = this.terminal.Restore()
+
+	return this.TargetCount
 }
 
 // [locks mutex]
-func (this *PBar[T]) saveCursorPosition() {
-	if this.testing {
+func (this *PBar[T]) updateBar() {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.dirty.Store(false) // changes from here on are painted by the next frame
+	this.finalFrame = this.finished
+
+	current := this.current()
+	this.indeterminate = this.TargetCount == 0
+	var percentCompleted float32
+	if this.indeterminate {
+		this.barCounts = comma(current)
+		this.barPercent = ""
+	} else {
+		percentCompleted = float32(current) / float32(this.TargetCount)
+		this.barCounts = fmt.Sprintf("%s/%s", comma(current), comma(this.TargetCount))
+		this.barPercent = fmt.Sprintf("%d%%", int(percentCompleted*100.0))
+	}
+	this.fraction = percentCompleted
+
+	this.renderedAt = this.now()
+	this.remaining = float64(this.TargetCount) - float64(current)
+	this.rate.sample(this.renderedAt, float64(current))
+
+	this.snapshot = Snapshot{
+		Current: int64(current),
+		Target:  int64(this.TargetCount),
+		Elapsed: this.rate.elapsed(this.renderedAt),
+		Rate:    this.rate.rate,
+		Width:   this.barLength,
+	}
+	this.runDecorators()
+	if this.autoWidth {
+		this.fitWidth()
+	}
+
+	switch {
+	case this.indeterminate:
+		this.paintIndeterminate()
+	case len(this.partials) > 0:
+		this.paintPartial(percentCompleted)
+	default:
+		this.paintClassic(percentCompleted)
+	}
+	this.placeHead()
+	this.frames++
+}
+
+func (this *PBarSupport) paintClassic(percentCompleted float32) {
+	completed := int(percentCompleted * float32(this.barLength))
+	this.filledFrom, this.filledTo = 1, min(completed, this.barLength)
+
+	for i := 1; i <= this.barLength; i++ {
+		if i <= completed {
+			this.barVisual[i] = this.completedRune(this.barCompleted)
+		} else {
+			this.barVisual[i] = this.barUncompleted
+		}
+	}
+}
+
+// paintPartial renders the boundary cell with a partial fill, giving as many steps per
+// cell as there are partials; completed cells are drawn with the last (full) partial.
+func (this *PBarSupport) paintPartial(percentCompleted float32) {
+	steps := len(this.partials)
+	units := int(percentCompleted * float32(this.barLength*steps))
+	completed, partial := units/steps, units%steps
+	this.filledFrom, this.filledTo = 1, min(completed, this.barLength)
+	if partial > 0 {
+		this.filledTo = min(completed+1, this.barLength)
+	}
+
+	for i := 1; i <= this.barLength; i++ {
+		switch {
+		case i <= completed:
+			this.barVisual[i] = this.completedRune(this.partials[steps-1])
+		case i == completed+1 && partial > 0:
+			this.barVisual[i] = this.partials[partial-1]
+		default:
+			this.barVisual[i] = this.barUncompleted
+		}
+	}
+}
+
+// placeHead reserves the cells at the leading edge of the completed cells for the head,
+// which may span several cells. A head that does not fit in the bar is not drawn.
+func (this *PBarSupport) placeHead() {
+	this.head, this.headFrom, this.headTo = this.barHead, 0, 0
+	if this.fraction >= 1 && this.barFullHead != "" {
+		this.head = this.barFullHead
+	}
+
+	cellWidth := max(this.cellWidth, 1)
+	width := (displayWidth(this.head) + cellWidth - 1) / cellWidth
+	if this.indeterminate || width == 0 || width > this.barLength || this.filledTo < 1 {
 		return
 	}
 
-	if this.openTty() != nil {
+	this.headTo = max(this.filledTo, width)
+	this.headFrom = this.headTo - width + 1
+	this.filledTo = this.headFrom - 1
+}
+
+// completedRune returns the rune for completed cells, which changes once the bar is aborted or failed.
+func (this *PBarSupport) completedRune(completed rune) rune {
+	if this.barEnded != 0 {
+		return this.barEnded
+	}
+	return completed
+}
+
+// smoothBlocks fill one to eight eighths of a cell.
+var smoothBlocks = []rune{'▏', '▎', '▍', '▌', '▋', '▊', '▉', '█'}
+
+// [locks mutex]
+func (this *PBar[T]) repaint() {
+	if this.isPlain() {
+		this.paintPlain()
 		return
 	}
-	defer this.closeTty()
 
 	this.mutex.Lock()
 	defer this.mutex.Unlock()
 
-	out := make([]byte, 6)
-	_, _ = this.terminal.Write([]byte{13, 27, '[', '6', 'n'})
-	_, _ = this.terminal.Read(out)
-	split := strings.Split(string(out[2:]), ";")
-	if len(split) > 1 {
-		this.cursorPosition.row = atoi8(split[0])
-		this.cursorPosition.col = atoi8(split[1])
+	var frame strings.Builder
+	frame.WriteString(this.clearFrame())
+	this.paintLine(&frame)
+	_, _ = io.WriteString(this.output, frame.String())
+}
+
+// clearFrame returns the sequences that move the cursor back to the start of the previous
+// frame, relative to where the frame left it, and erase the frame.
+func (this *PBarSupport) clearFrame() string {
+	if this.rows > 1 {
+		return fmt.Sprintf("%c[%dA%c%c[J", 27, this.rows-1, 13, 27) // up to the first row the line wrapped from
+	}
+	return fmt.Sprintf("%c%c[2K", 13, 27)
+}
+
+// paintLine renders the line, followed by a space, at the cursor and records the rows it takes.
+func (this *PBarSupport) paintLine(frame *strings.Builder) {
+	this.rows = 1 // a cleared line leaves the cursor on its empty row
+	if line := this.line(); line != "" {
+		_, _ = fmt.Fprintf(frame, "%s%c", line, 32)
+		this.rows = this.rowsFor(displayWidth(line) + 1)
 	}
 }
 
 // [locks mutex]
-func (this *PBar[T]) restoreCursorPosition() {
-	if this.testing {
+func (this *PBar[T]) attach(owner *Container) {
+	this.mutex.Lock()
+	this.contained = true
+	this.colors.enable(owner.output, os.Getenv("NO_COLOR")) // the Container's output is drawn on instead
+	this.mutex.Unlock()
+	this.owner.Store(owner)
+
+	this.initializeBar()
+}
+
+// [locks mutex]
+func (this *PBar[T]) frame() string {
+	this.updateBar()
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.line()
+}
+
+// checkOutput decides how to render: in place, tracking the terminal width, for terminals;
+// as plain lines for files and pipes, or when there is no terminal to draw on, e.g. under
+// cron; and in place for any other writer.
+func (this *PBarSupport) checkOutput() {
+	file, ok := this.output.(*os.File)
+	if !ok {
 		return
 	}
+	this.interactive = isTerminal(file)
+	if this.interactive {
+		terminal, err := term.Open(this.tty)
+		if err == nil {
+			_ = terminal.Close()
+		}
+		this.interactive = err == nil
+	}
+	this.plain = this.plain || !this.interactive
+}
 
+// [locks mutex]
+func (this *PBar[T]) isPlain() bool {
 	this.mutex.Lock()
 	defer this.mutex.Unlock()
 
-	if this.cursorPosition.row == 0 && this.cursorPosition.col == 0 {
-		return
+	return this.plain
+}
+
+// lastFrame renders the line again without updating the bar.
+// [locks mutex]
+func (this *PBar[T]) lastFrame() string {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.line()
+}
+
+func (this *PBarSupport) line() string {
+	if this.hidden {
+		return ""
 	}
-	fmt.Printf("%c%c%d;%dH", 27, '[', this.cursorPosition.row, this.cursorPosition.col)
+	return this.decorate(this.execute(this.template))
 }
 
 // CountFileLines count newline characters in a file
@@ -262,17 +757,21 @@ func CountFileLines(path string) (count int, err error) {
 // [locks mutex]
 func (this *PBar[T]) initializeBar() {
 	this.mutex.Lock()
-	this.barVisual = make([]rune, this.barLength+2) // plus beginning and end markers
-	this.barVisual[0] = this.barLeft
-	this.barVisual[this.barLength+1] = this.barRight
+	this.setBarLength(this.barLength)
+	this.rate.start(this.now(), float64(this.current()))
 	this.mutex.Unlock()
 
 	this.updateBar()
 }
 
-func atoi8(val string) int8 {
-	strVal, _ := strconv.Atoi(val)
-	return int8(strVal)
+func (this *PBarSupport) setBarLength(length int) {
+	this.barLength = length
+	if len(this.barVisual) != length+2 {
+		this.barVisual = make([]rune, length+2) // plus beginning and end markers
+	}
+	this.barVisual[0] = this.barLeft
+	this.barVisual[length+1] = this.barRight
+	this.measureCells()
 }
 
 func comma[T integer](n T) string {
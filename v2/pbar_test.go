@@ -2,7 +2,12 @@ package pbar
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"io"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -48,17 +53,17 @@ func (this *PBarFixture) TestStart() {
 
 	progressBar.Update(500)
 	time.Sleep(time.Millisecond * 100)
-	this.So(safeRead(), should.Resemble, []rune("\x0D[     ] (0/1,000) 0% "))
+	this.So(safeRead(), should.Resemble, []rune("\x0D\x1b[2K[     ] (0/1,000) 0% "))
 
 	time.Sleep(time.Millisecond * 300)
 	this.So(safeRead(), should.Resemble,
-		[]rune("\x0D[     ] (0/1,000) 0% \x0D[==   ] (500/1,000) 50% "))
+		[]rune("\x0D\x1b[2K[     ] (0/1,000) 0% \x0D\x1b[2K[==   ] (500/1,000) 50% "))
 
 	progressBar.Finish()
 
-	//time.Sleep(time.Millisecond * 300)
+	// Finish returns once the render goroutine has painted the final frame.
 	this.So(safeRead(), should.Resemble,
-		[]rune("\x0D[     ] (0/1,000) 0% \x0D[==   ] (500/1,000) 50% \x0D[=====] (1,000/1,000) 100% \x0D[=====] (1,000/1,000) 100% "))
+		[]rune("\x0D\x1b[2K[     ] (0/1,000) 0% \x0D\x1b[2K[==   ] (500/1,000) 50% \x0D\x1b[2K[=====] (1,000/1,000) 100% "))
 }
 
 func (this *PBarFixture) TestCountFileLines() {
@@ -74,15 +79,14 @@ func (this *PBarFixture) TestCountFileLines() {
 }
 
 func (this *PBarFixture) TestNoTerminal() {
+	// When output is redirected to a file or pipe, as when a process using pbar is run with cron,
+	// progress is written as plain newline-terminated lines.
+	output, err := os.CreateTemp("", "pbar")
+	this.So(err, should.BeNil)
+	defer func() { _ = os.Remove(output.Name()) }()
+	defer func() { _ = output.Close() }()
 	progressBar := NewPBar(1000,
-		RefreshIntervalMilliseconds(250), BarLength(5))
-	// Setting tty to a value other than the default (/dev/tty) will cause the tty open to fail,
-	// simulating what occurs when a process using pbar is run in the background with no tty available like
-	// when run with cron. In this case, all output is sent to io.Discard.
-	//
-	// Because no output is generated, this test demonstrates that a failure to
-	// open the tty does not affect the calling program (like this test) from operating normally.
-	progressBar.tty = "FALSE"
+		RefreshIntervalMilliseconds(250), BarLength(5), OutputWriter(output))
 	progressBar.Start()
 	progressBar.Update(250)
 	time.Sleep(time.Millisecond * 250)
@@ -90,5 +94,302 @@ func (this *PBarFixture) TestNoTerminal() {
 	time.Sleep(time.Millisecond * 250)
 	progressBar.Update(750)
 	progressBar.Finish()
-	this.So(progressBar.currentCount, should.Equal, progressBar.TargetCount)
+	this.So(progressBar.current(), should.Equal, progressBar.TargetCount)
+
+	written, _ := os.ReadFile(output.Name())
+	this.So(string(written), should.StartWith, "[     ] (0/1,000) 0%\n")
+	this.So(string(written), should.EndWith, "[=====] (1,000/1,000) 100%\n")
+	this.So(string(written), should.NotContainSubstring, "\r")
+}
+
+func (this *PBarFixture) TestOtherWritersAreRepaintedInPlace() {
+	progressBar := NewPBar(10, BarLength(5), OutputWriter(new(bytes.Buffer)))
+	progressBar.checkOutput()
+
+	this.So(progressBar.interactive, should.BeFalse)
+	this.So(progressBar.plain, should.BeFalse)
+}
+
+func (this *PBarFixture) TestRepaintReturnsToTheStartOfAWrappedLine() {
+	output := new(bytes.Buffer)
+	progressBar := NewPBar(10, BarLength(20), OutputWriter(output))
+	progressBar.resize(20)
+	progressBar.initializeBar()
+
+	progressBar.repaint()
+	progressBar.repaint()
+
+	line := "[" + strings.Repeat(" ", 20) + "] (0/10) 0% "
+	this.So(output.String(), should.Equal, "\r\x1b[2K"+line+"\x1b[1A\r\x1b[J"+line)
+}
+
+func (this *PBarFixture) TestSmoothBar() {
+	progressBar := NewPBar(100, BarLength(4), BarSmooth(), BarUncompleted('·'))
+	progressBar.initializeBar()
+
+	for current, expected := range map[int]string{
+		0:   "[····]",
+		4:   "[▏···]",
+		30:  "[█▏··]",
+		50:  "[██··]",
+		90:  "[███▌]",
+		100: "[████]",
+	} {
+		progressBar.Update(current)
+		progressBar.updateBar()
+		this.So(string(progressBar.barVisual), should.Equal, expected)
+	}
+}
+
+func (this *PBarFixture) TestBarHead() {
+	progressBar := NewPBar(100, BarLength(6), BarHead("=>"), BarFullHead("=="))
+	progressBar.initializeBar()
+
+	for current, expected := range map[int]string{
+		0:   "[      ]",
+		17:  "[=>    ]",
+		50:  "[==>   ]",
+		84:  "[====> ]",
+		100: "[======]",
+	} {
+		progressBar.Update(current)
+		progressBar.updateBar()
+		this.So(progressBar.bar(), should.Equal, expected)
+	}
+}
+
+func (this *PBarFixture) TestWideBarHeadKeepsTheCellCount() {
+	progressBar := NewPBar(100, BarLength(6), BarHead("🚀"))
+	progressBar.initializeBar()
+
+	for current, expected := range map[int]string{
+		17:  "[🚀    ]",
+		50:  "[=🚀   ]",
+		100: "[====🚀]",
+	} {
+		progressBar.Update(current)
+		progressBar.updateBar()
+		this.So(progressBar.bar(), should.Equal, expected)
+		this.So(displayWidth(progressBar.bar()), should.Equal, 8)
+	}
+}
+
+func (this *PBarFixture) TestAddTargetKeepsRenderLoopRunning() {
+	progressBar := NewPBar(uint(10), OutputWriter(io.Discard), RefreshIntervalMilliseconds(5))
+	progressBar.Start()
+
+	progressBar.AddTarget(10)
+	progressBar.Update(10)
+	time.Sleep(time.Millisecond * 20)
+	this.So(progressBar.Target(), should.Equal, uint(20))
+
+	progressBar.mutex.Lock()
+	this.So(progressBar.line(), should.Equal,
+		"["+strings.Repeat("=", 25)+strings.Repeat(" ", 25)+"] (10/20) 50%")
+	progressBar.mutex.Unlock()
+
+	progressBar.SetTarget(15)
+	progressBar.Update(15)
+	time.Sleep(time.Millisecond * 20)
+
+	progressBar.mutex.Lock()
+	this.So(progressBar.line(), should.EndWith, "] (15/15) 100%")
+	progressBar.mutex.Unlock()
+}
+
+func (this *PBarFixture) TestConcurrentAdd() {
+	progressBar := NewPBar(int32(4000))
+
+	var workers sync.WaitGroup
+	for range 4 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for range 500 {
+				progressBar.Increment()
+				progressBar.Add(2)
+				progressBar.Add(-1)
+			}
+		}()
+	}
+	workers.Wait()
+
+	this.So(progressBar.current(), should.Equal, int32(4000))
+}
+
+func (this *PBarFixture) TestStartContextCancellation() {
+	outBuf := new(bytes.Buffer)
+	progressBar := NewPBar(100, OutputWriter(outBuf), RefreshIntervalMilliseconds(10), BarLength(4))
+	ctx, cancel := context.WithCancel(context.Background())
+	progressBar.StartContext(ctx)
+
+	progressBar.Update(30)
+	cancel()
+	<-progressBar.Done()
+	this.So(outBuf.String(), should.EndWith, "\r\x1b[2K[=   ] (30/100) cancelled ")
+
+	progressBar.Finish()
+	this.So(outBuf.String(), should.EndWith, "\r\x1b[2K[=   ] (30/100) cancelled ")
+}
+
+func (this *PBarFixture) TestReachingTheTargetKeepsRenderingUntilFinish() {
+	outBuf := new(bytes.Buffer)
+	progressBar := NewPBar(10, OutputWriter(outBuf), RefreshIntervalMilliseconds(5), BarLength(2))
+	progressBar.Start()
+
+	progressBar.Update(10)
+	time.Sleep(time.Millisecond * 20)
+	select {
+	case <-progressBar.Done():
+		this.Error("the render loop stopped before Finish")
+	default:
+	}
+
+	progressBar.Finish()
+	<-progressBar.Done()
+	this.So(outBuf.String(), should.EndWith, "\r\x1b[2K[==] (10/10) 100% ")
+}
+
+func (this *PBarFixture) TestGrowingTheTargetAfterItWasReached() {
+	outBuf := new(bytes.Buffer)
+	progressBar := NewPBar(10, OutputWriter(outBuf), RefreshIntervalMilliseconds(5), BarLength(4))
+	progressBar.Start()
+	defer progressBar.Finish()
+
+	progressBar.Update(10)
+	time.Sleep(time.Millisecond * 20)
+	progressBar.AddTarget(10)
+	progressBar.Update(15)
+	time.Sleep(time.Millisecond * 20)
+
+	progressBar.mutex.Lock()
+	defer progressBar.mutex.Unlock()
+	this.So(outBuf.String(), should.EndWith, "\r\x1b[2K[=== ] (15/20) 75% ")
+}
+
+func (this *PBarFixture) TestShrinkingTheTargetBelowTheCountCompletesTheBar() {
+	outBuf := new(bytes.Buffer)
+	progressBar := NewPBar(10, OutputWriter(outBuf), RefreshIntervalMilliseconds(1000), BarLength(2))
+	progressBar.Start()
+	defer progressBar.Finish()
+
+	progressBar.Update(8)
+	progressBar.AddTarget(-4) // completion is painted without waiting for the interval
+	time.Sleep(time.Millisecond * 20)
+
+	progressBar.mutex.Lock()
+	defer progressBar.mutex.Unlock()
+	this.So(outBuf.String(), should.EndWith, "(8/6) 133% ")
+}
+
+func (this *PBarFixture) TestOvershootingTheTargetCompletesTheBar() {
+	outBuf := new(bytes.Buffer)
+	progressBar := NewPBar(10, OutputWriter(outBuf), RefreshIntervalMilliseconds(1000), BarLength(2))
+	progressBar.Start()
+	defer progressBar.Finish()
+
+	progressBar.Add(7)
+	progressBar.Add(7)
+	time.Sleep(time.Millisecond * 20)
+
+	progressBar.mutex.Lock()
+	defer progressBar.mutex.Unlock()
+	this.So(outBuf.String(), should.EndWith, "[==] (14/10) 140% ")
+}
+
+func (this *PBarFixture) TestAbortLeavesTheBarWhereItStopped() {
+	outBuf := new(bytes.Buffer)
+	progressBar := NewPBar(100, OutputWriter(outBuf), BarLength(4), BarAbortedCompleted('-'))
+	progressBar.initializeBar()
+	progressBar.Update(50)
+
+	progressBar.Abort()
+	progressBar.Finish()
+
+	this.So(outBuf.String(), should.Equal, "\r\x1b[2K[--  ] (50/100) aborted ")
+}
+
+func (this *PBarFixture) TestFinalFramesOfBarsThatWereNeverStarted() {
+	for expected, end := range map[string]func(*PBar[int]){
+		"\r\x1b[2K[= ] (5/10) aborted ":           (*PBar[int]).Abort,
+		"\r\x1b[2K[= ] (5/10) failed: disk full ": func(progressBar *PBar[int]) { progressBar.Fail(errors.New("disk full")) },
+		"\r\x1b[2K[==] (10/10) 100% ":             (*PBar[int]).Finish,
+	} {
+		outBuf := new(bytes.Buffer)
+		progressBar := NewPBar(10, OutputWriter(outBuf), BarLength(2))
+		progressBar.Update(5)
+
+		end(progressBar)
+
+		this.So(outBuf.String(), should.Equal, expected)
+	}
+}
+
+func (this *PBarFixture) TestWaitReturnsForBarsThatWereNeverStarted() {
+	progressBar := NewPBar(10, OutputWriter(io.Discard))
+	progressBar.Wait()
+
+	progressBar.Abort()
+	<-progressBar.Done()
+}
+
+func (this *PBarFixture) TestClearOnAbort() {
+	outBuf := new(bytes.Buffer)
+	progressBar := NewPBar(100, OutputWriter(outBuf), BarLength(4), ClearOnAbort())
+	progressBar.initializeBar()
+
+	progressBar.Abort()
+
+	this.So(outBuf.String(), should.Equal, "\r\x1b[2K")
+}
+
+func (this *PBarFixture) TestFailShowsTheError() {
+	outBuf := new(bytes.Buffer)
+	progressBar := NewPBar(100, OutputWriter(outBuf), BarLength(4), BarFailedCompleted('x'))
+	progressBar.initializeBar()
+	progressBar.Update(75)
+
+	progressBar.Fail(errors.New("disk full"))
+	progressBar.Fail(errors.New("ignored"))
+
+	this.So(outBuf.String(), should.Equal, "\r\x1b[2K[xxx ] (75/100) failed: disk full ")
+}
+
+func (this *PBarFixture) TestIdleBarIsNotRepainted() {
+	outBuf := new(bytes.Buffer)
+	progressBar := NewPBar(10, OutputWriter(outBuf), RefreshIntervalMilliseconds(5), BarLength(2))
+	progressBar.Start()
+	time.Sleep(time.Millisecond * 50)
+
+	progressBar.mutex.Lock()
+	this.So(outBuf.String(), should.Equal, "\r\x1b[2K[  ] (0/10) 0% ")
+	progressBar.mutex.Unlock()
+	progressBar.Finish()
+}
+
+func (this *PBarFixture) TestChangesArePaintedWithoutWaitingForTheInterval() {
+	outBuf := new(bytes.Buffer)
+	progressBar := NewPBar(10, OutputWriter(outBuf), RefreshIntervalMilliseconds(100), BarLength(2))
+	progressBar.Start()
+	time.Sleep(time.Millisecond * 150) // idle for longer than the interval
+
+	progressBar.Update(5)
+	time.Sleep(time.Millisecond * 20)
+	progressBar.mutex.Lock()
+	this.So(outBuf.String(), should.EndWith, "\r\x1b[2K[= ] (5/10) 50% ")
+	progressBar.mutex.Unlock()
+
+	progressBar.Update(6) // throttled until the interval passes
+	time.Sleep(time.Millisecond * 20)
+	progressBar.mutex.Lock()
+	this.So(outBuf.String(), should.EndWith, "\r\x1b[2K[= ] (5/10) 50% ")
+	progressBar.mutex.Unlock()
+
+	progressBar.Update(10) // completion is painted immediately
+	time.Sleep(time.Millisecond * 20)
+	progressBar.mutex.Lock()
+	this.So(outBuf.String(), should.EndWith, "\r\x1b[2K[= ] (5/10) 50% \r\x1b[2K[==] (10/10) 100% ")
+	progressBar.mutex.Unlock()
+
+	progressBar.Finish()
 }
@@ -0,0 +1,68 @@
+package pbar
+
+import (
+	"fmt"
+	"time"
+)
+
+// plainLog records the most recent line written in plain mode.
+type plainLog struct {
+	at    time.Time // when the line was rendered, zero before the first line
+	step  int       // the percent step reached, see PlainPercentStep
+	final bool      // the final line has been written
+}
+
+// logDue reports whether a plain line is due: the first line, one for each percent step
+// reached, one each plain interval, and the final line. No lines follow the final line
+// unless the target grows past the count again.
+func (this *PBarSupport) logDue(final bool) bool {
+	if this.logged.final && !final {
+		this.logged = plainLog{at: this.logged.at} // the target has grown since the bar reached it
+	}
+	if this.logged.final {
+		return false
+	}
+
+	step := this.logged.step
+	if !this.indeterminate && this.plainStep > 0 {
+		step = max(step, int(this.fraction*100)/this.plainStep)
+	}
+
+	due := this.logged.at.IsZero() || final || step > this.logged.step ||
+		(this.plainInterval > 0 && this.renderedAt.Sub(this.logged.at) >= this.plainInterval)
+	if due {
+		this.logged = plainLog{at: this.renderedAt, step: step, final: final}
+	}
+	return due
+}
+
+// paintPlain writes the line, newline-terminated, when a plain line is due.
+// [locks mutex]
+func (this *PBar[T]) paintPlain() {
+	final := this.ended()
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if !this.logDue(final) {
+		return
+	}
+	if line := this.line(); line != "" {
+		_, _ = fmt.Fprintln(this.output, line)
+	}
+}
+
+// plainFrame renders the bar and returns its line if a plain line is due, or else "".
+// [locks mutex]
+func (this *PBar[T]) plainFrame(final bool) string {
+	this.updateBar()
+	final = final || this.ended()
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if !this.logDue(final) {
+		return ""
+	}
+	return this.line()
+}
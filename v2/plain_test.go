@@ -0,0 +1,120 @@
+package pbar
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/smarty/assertions/should"
+	"github.com/smarty/gunit"
+)
+
+func TestPlainFixture(t *testing.T) {
+	gunit.Run(new(PlainFixture), t)
+}
+
+type PlainFixture struct {
+	*gunit.Fixture
+	now    time.Time
+	output *bytes.Buffer
+}
+
+func (this *PlainFixture) Setup() {
+	this.now = time.Now()
+	this.output = new(bytes.Buffer)
+}
+
+func (this *PlainFixture) clock() time.Time { return this.now }
+
+func (this *PlainFixture) newBar(target int, options ...Option) *PBar[int] {
+	options = append([]Option{OutputWriter(this.output), PlainOutput(), BarLength(4)}, options...)
+	progressBar := NewPBar(target, options...)
+	progressBar.now = this.clock
+	progressBar.initializeBar()
+	return progressBar
+}
+
+func (this *PlainFixture) paint(progressBar *PBar[int], current int) {
+	progressBar.Update(current)
+	progressBar.updateBar()
+	progressBar.repaint()
+}
+
+func (this *PlainFixture) TestLineForEachPercentStep() {
+	progressBar := this.newBar(100, PlainPercentStep(25))
+
+	for _, current := range []int{0, 10, 24, 25, 30, 60, 99} {
+		this.paint(progressBar, current)
+	}
+	progressBar.Finish()
+
+	this.So(this.output.String(), should.Equal, ""+
+		"[    ] (0/100) 0%\n"+
+		"[=   ] (25/100) 25%\n"+
+		"[==  ] (60/100) 60%\n"+
+		"[=== ] (99/100) 99%\n"+
+		"[====] (100/100) 100%\n")
+}
+
+func (this *PlainFixture) TestLineEachInterval() {
+	progressBar := this.newBar(0, PlainIntervalSeconds(5))
+
+	this.paint(progressBar, 10)
+	this.now = this.now.Add(4 * time.Second)
+	this.paint(progressBar, 20)
+	this.now = this.now.Add(time.Second)
+	this.paint(progressBar, 30)
+
+	this.So(this.output.String(), should.Equal, ""+
+		"[ =  ] (10) 0.0/s\n"+
+		"[   =] (30) 6.0/s\n")
+}
+
+func (this *PlainFixture) TestNothingFollowsTheFinalLine() {
+	progressBar := this.newBar(100)
+
+	this.paint(progressBar, 0)
+	progressBar.Abort()
+	this.now = this.now.Add(time.Minute)
+	this.paint(progressBar, 50)
+
+	this.So(this.output.String(), should.Equal, ""+
+		"[    ] (0/100) 0%\n"+
+		"[    ] (0/100) aborted\n")
+}
+
+func (this *PlainFixture) TestContainerWritesLinesForEachBar() {
+	container := NewContainer(OutputWriter(this.output), PlainOutput())
+	first := NewPBar(100, BarLabel("one "), BarLength(4), PlainPercentStep(50))
+	second := NewPBar(100, BarLabel("two "), BarLength(4), PlainPercentStep(50))
+	container.Add(first)
+	container.Add(second)
+
+	container.repaint()
+	first.Update(50)
+	second.Update(10)
+	container.repaint()
+	container.repaintFinal()
+
+	this.So(this.output.String(), should.Equal, ""+
+		"one [    ] (0/100) 0%\n"+
+		"two [    ] (0/100) 0%\n"+
+		"one [==  ] (50/100) 50%\n"+
+		"one [==  ] (50/100) 50%\n"+
+		"two [    ] (10/100) 10%\n")
+}
+
+func (this *PlainFixture) TestLinesResumeWhenTheTargetGrowsPastTheCount() {
+	progressBar := this.newBar(10, PlainPercentStep(50))
+
+	this.paint(progressBar, 10)
+	this.paint(progressBar, 10)
+	progressBar.AddTarget(10)
+	this.paint(progressBar, 15)
+	progressBar.Finish()
+
+	this.So(this.output.String(), should.Equal, ""+
+		"[====] (10/10) 100%\n"+
+		"[=== ] (15/20) 75%\n"+
+		"[====] (20/20) 100%\n")
+}
@@ -0,0 +1,141 @@
+package pbar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// rateEstimator tracks the throughput observed by the render loop as an
+// exponential moving average so that estimates do not jump between frames.
+type rateEstimator struct {
+	started   time.Time
+	sampled   time.Time
+	lastCount float64
+	rate      float64 // items per second
+	primed    bool
+	smoothing float64 // weight given to the newest sample, between 0 and 1
+}
+
+func (this *rateEstimator) start(now time.Time, count float64) {
+	this.started = now
+	this.sampled = now
+	this.lastCount = count
+	this.rate = 0
+	this.primed = false
+}
+
+func (this *rateEstimator) sample(now time.Time, count float64) {
+	interval := now.Sub(this.sampled).Seconds()
+	if interval <= 0 {
+		return
+	}
+
+	instant := (count - this.lastCount) / interval
+	if this.primed {
+		this.rate += this.smoothing * (instant - this.rate)
+	} else {
+		this.rate = instant
+		this.primed = true
+	}
+
+	this.sampled = now
+	this.lastCount = count
+}
+
+func (this *rateEstimator) elapsed(now time.Time) time.Duration {
+	return now.Sub(this.started)
+}
+
+// remaining reports how long the remaining items will take at the current rate.
+func (this *rateEstimator) remaining(items float64) (time.Duration, bool) {
+	if items <= 0 {
+		return 0, true
+	}
+	if this.rate <= 0 {
+		return 0, false
+	}
+	return time.Duration(items / this.rate * float64(time.Second)), true
+}
+
+// statistics joins the summary fields enabled by the Show* options.
+func (this *PBarSupport) statistics() string {
+	var fields []string
+
+	if this.showRate || this.indeterminate {
+		fields = append(fields, this.formatRate())
+	}
+	if this.showElapsed {
+		fields = append(fields, "elapsed "+this.formatElapsed())
+	}
+	if this.showRemaining {
+		fields = append(fields, "eta "+this.formatRemaining())
+	}
+	if this.showFinishTime {
+		fields = append(fields, "finish "+this.formatFinishTime())
+	}
+
+	return strings.Join(fields, " ")
+}
+
+func (this *PBarSupport) formatElapsed() string {
+	return clock(this.rate.elapsed(this.renderedAt))
+}
+
+func (this *PBarSupport) formatRemaining() string {
+	if this.indeterminate {
+		return unknownClock
+	}
+	if remaining, known := this.rate.remaining(this.remaining); known {
+		return clock(remaining)
+	}
+	return unknownClock
+}
+
+func (this *PBarSupport) formatFinishTime() string {
+	if this.indeterminate {
+		return unknownClock
+	}
+	if remaining, known := this.rate.remaining(this.remaining); known {
+		return this.renderedAt.Add(remaining).Format(time.TimeOnly)
+	}
+	return unknownClock
+}
+
+func (this *PBarSupport) formatRate() string {
+	rate := max(this.rate.rate, 0)
+	if this.rateBytes {
+		return byteSize(rate) + "/s"
+	}
+
+	unit := this.rateUnit
+	if unit != "" {
+		unit = " " + unit
+	}
+	if rate < 10 {
+		return fmt.Sprintf("%.1f%s/s", rate, unit)
+	}
+	return fmt.Sprintf("%s%s/s", comma(int64(rate+0.5)), unit)
+}
+
+// byteSize scales a byte count to the largest binary unit that keeps it at or above 1.
+func byteSize(bytes float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	unit := 0
+	for bytes >= 1024 && unit < len(units)-1 {
+		bytes /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", bytes, units[unit])
+	}
+	return fmt.Sprintf("%.1f %s", bytes, units[unit])
+}
+
+const unknownClock = "--:--:--"
+
+// clock formats a duration as h:mm:ss, rounded to the second.
+func clock(duration time.Duration) string {
+	seconds := int64(duration.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
@@ -0,0 +1,100 @@
+package pbar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smarty/assertions/should"
+	"github.com/smarty/gunit"
+)
+
+func TestRateFixture(t *testing.T) {
+	gunit.Run(new(RateFixture), t)
+}
+
+type RateFixture struct {
+	*gunit.Fixture
+	now time.Time
+}
+
+func (this *RateFixture) Setup() {
+	this.now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (this *RateFixture) clock() time.Time { return this.now }
+
+func (this *RateFixture) TestRateIsSmoothed() {
+	estimator := rateEstimator{smoothing: 0.5}
+	estimator.start(this.now, 0)
+
+	estimator.sample(this.now.Add(time.Second), 100)
+	this.So(estimator.rate, should.Equal, 100)
+
+	estimator.sample(this.now.Add(2*time.Second), 400)
+	this.So(estimator.rate, should.Equal, 200)
+}
+
+func (this *RateFixture) TestRemainingUnknownUntilProgressIsObserved() {
+	estimator := rateEstimator{smoothing: 0.5}
+	estimator.start(this.now, 0)
+
+	_, known := estimator.remaining(100)
+	this.So(known, should.BeFalse)
+
+	estimator.sample(this.now.Add(time.Second), 10)
+	remaining, known := estimator.remaining(90)
+	this.So(known, should.BeTrue)
+	this.So(remaining, should.Equal, 9*time.Second)
+}
+
+func (this *RateFixture) TestTimingSummary() {
+	progressBar := NewPBar(1000, BarLength(4), ShowElapsed(), ShowRemaining(), ShowFinishTime())
+	progressBar.now = this.clock
+	progressBar.initializeBar()
+
+	this.So(progressBar.line(), should.Equal,
+		"[    ] (0/1,000) 0% elapsed 0:00:00 eta --:--:-- finish --:--:--")
+
+	this.now = this.now.Add(90 * time.Second)
+	progressBar.Update(250)
+	progressBar.updateBar()
+
+	this.So(progressBar.line(), should.Equal,
+		"[=   ] (250/1,000) 25% elapsed 0:01:30 eta 0:04:30 finish 12:06:00")
+}
+
+func (this *RateFixture) TestTimingHiddenByDefault() {
+	progressBar := NewPBar(10, BarLength(2))
+	progressBar.initializeBar()
+
+	this.So(progressBar.line(), should.Equal, "[  ] (0/10) 0%")
+}
+
+func (this *RateFixture) TestRateSummary() {
+	progressBar := NewPBar(100_000, BarLength(4), ShowRate("records"), ShowElapsed())
+	progressBar.now = this.clock
+	progressBar.initializeBar()
+
+	this.now = this.now.Add(2 * time.Second)
+	progressBar.Update(12_500)
+	progressBar.updateBar()
+
+	this.So(progressBar.line(), should.Equal, "[    ] (12,500/100,000) 12% 6,250 records/s elapsed 0:00:02")
+}
+
+func (this *RateFixture) TestRateFormatting() {
+	support := DefaultPBarSupport()
+	support.showRate = true
+
+	support.rate.rate = 2.25
+	this.So(support.formatRate(), should.Equal, "2.2/s")
+
+	support.rateBytes = true
+	this.So(support.formatRate(), should.Equal, "2 B/s")
+
+	support.rate.rate = 1536
+	this.So(support.formatRate(), should.Equal, "1.5 KiB/s")
+
+	support.rate.rate = 5 * 1024 * 1024
+	this.So(support.formatRate(), should.Equal, "5.0 MiB/s")
+}
@@ -0,0 +1,127 @@
+package pbar
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TemplateDefault reproduces the classic layout: label, bar, counts and percent,
+// followed by any statistics enabled with the Show* options.
+const TemplateDefault = "{label}{bar} ({counts}) {percent} {stats}"
+
+// templateFields maps each template token to the text it renders.
+var templateFields = map[string]func(*PBarSupport) string{
+	"label":   func(c *PBarSupport) string { return c.colors.paint(c.colors.label, c.label()) },
+	"bar":     (*PBarSupport).bar,
+	"counts":  func(c *PBarSupport) string { return c.barCounts },
+	"percent": (*PBarSupport).percent,
+	"rate":    (*PBarSupport).formatRate,
+	"elapsed": (*PBarSupport).formatElapsed,
+	"eta":     (*PBarSupport).formatRemaining,
+	"finish":  (*PBarSupport).formatFinishTime,
+	"stats":   (*PBarSupport).statistics,
+}
+
+// templateSegment is either literal text or the name of a field to render.
+type templateSegment struct {
+	literal string
+	field   string
+}
+
+// parseTemplate splits a layout such as "{label} {bar} {percent}" into segments.
+// Literal braces are written as "{{" and "}}".
+func parseTemplate(text string) (segments []templateSegment, err error) {
+	var literal strings.Builder
+
+	for i := 0; i < len(text); i++ {
+		switch {
+		case strings.HasPrefix(text[i:], "{{"), strings.HasPrefix(text[i:], "}}"):
+			literal.WriteByte(text[i])
+			i++
+		case text[i] == '}':
+			return nil, fmt.Errorf("pbar: unexpected '}' at offset %d in template %q", i, text)
+		case text[i] == '{':
+			end := strings.IndexByte(text[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("pbar: unterminated field at offset %d in template %q", i, text)
+			}
+			field := text[i+1 : i+end]
+			if _, found := templateFields[field]; !found {
+				return nil, fmt.Errorf("pbar: unknown field {%s} in template %q", field, text)
+			}
+			if literal.Len() > 0 {
+				segments = append(segments, templateSegment{literal: literal.String()})
+				literal.Reset()
+			}
+			segments = append(segments, templateSegment{field: field})
+			i += end
+		default:
+			literal.WriteByte(text[i])
+		}
+	}
+
+	if literal.Len() > 0 {
+		segments = append(segments, templateSegment{literal: literal.String()})
+	}
+	return segments, nil
+}
+
+// percent renders the {percent} field, which reports the outcome once the bar has ended early.
+func (this *PBarSupport) percent() string {
+	if this.barOutcome != "" {
+		return this.colors.paint(this.colors.percent, this.barOutcome)
+	}
+	return this.colors.paint(this.colors.percent, this.barPercent)
+}
+
+// timeDependent reports whether the layout changes with the passage of time alone,
+// in which case it is repainted every refresh interval even without updates.
+func (this *PBarSupport) timeDependent() bool {
+	if len(this.prepended) > 0 || len(this.appended) > 0 {
+		return true // decorators may render anything
+	}
+
+	statistics := this.showRate || this.showElapsed || this.showRemaining || this.showFinishTime
+	for _, segment := range this.template {
+		switch segment.field {
+		case "rate", "elapsed", "eta", "finish":
+			return true
+		case "stats":
+			if statistics {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func mustParseTemplate(text string) []templateSegment {
+	segments, err := parseTemplate(text)
+	if err != nil {
+		panic(err)
+	}
+	return segments
+}
+
+// execute renders the segments. A space that follows an empty field is dropped
+// when the line already ends with one, so omitted fields leave no gaps.
+func (this *PBarSupport) execute(segments []templateSegment) string {
+	var line strings.Builder
+	collapse := false
+	for _, segment := range segments {
+		if segment.field != "" {
+			text := templateFields[segment.field](this)
+			line.WriteString(text)
+			collapse = text == ""
+			continue
+		}
+
+		literal := segment.literal
+		if collapse && strings.HasPrefix(literal, " ") && (line.Len() == 0 || strings.HasSuffix(line.String(), " ")) {
+			literal = literal[1:]
+		}
+		line.WriteString(literal)
+		collapse = false
+	}
+	return strings.TrimRight(line.String(), " ")
+}
@@ -0,0 +1,41 @@
+package pbar
+
+import (
+	"testing"
+
+	"github.com/smarty/assertions/should"
+	"github.com/smarty/gunit"
+)
+
+func TestTemplateFixture(t *testing.T) {
+	gunit.Run(new(TemplateFixture), t)
+}
+
+type TemplateFixture struct {
+	*gunit.Fixture
+}
+
+func (this *TemplateFixture) TestFieldsCanBeReorderedAndOmitted() {
+	progressBar := NewPBar(200, BarLength(4), BarLabel("load"), Template("{percent} {bar} {label} {{{counts}}}"))
+	progressBar.initializeBar()
+	progressBar.Update(100)
+	progressBar.updateBar()
+
+	this.So(progressBar.line(), should.Equal, "50% [==  ] load {100/200}")
+}
+
+func (this *TemplateFixture) TestParseErrors() {
+	for _, text := range []string{"{bar", "{bar}}x}", "{nope}", "bar}"} {
+		option, err := ParseTemplate(text)
+		this.So(option, should.BeNil)
+		this.So(err, should.NotBeNil)
+	}
+	this.So(func() { Template("{bar") }, should.Panic)
+}
+
+func (this *TemplateFixture) TestDefaultTemplateIncludesEnabledStatistics() {
+	progressBar := NewPBar(10, BarLength(2), ShowRemaining())
+	progressBar.initializeBar()
+
+	this.So(progressBar.line(), should.Equal, "[  ] (0/10) 0% eta --:--:--")
+}
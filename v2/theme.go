@@ -0,0 +1,73 @@
+package pbar
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Theme bundles the runes and colors that give a bar its look. Options given
+// after WithTheme override the individual choices made by the theme.
+type Theme struct {
+	Left, Right            rune
+	Completed, Uncompleted rune
+	Partials               []rune // fills of the boundary cell, ending with a full cell; see BarSmooth
+	Head, FullHead         string // see BarHead and BarFullHead
+	CompletedColor         Color
+	UncompletedColor       Color
+	BracketColor           Color
+}
+
+const (
+	ThemeClassic = "classic" // [=====     ]
+	ThemeBlocks  = "blocks"  // ▕█████▌    ▏
+	ThemeShaded  = "shaded"  // ▕█████▓░░░░▏
+	ThemeDots    = "dots"    // [●●●●●·····]
+	ThemeArrows  = "arrows"  // [=====>    ]
+)
+
+var (
+	themesMutex sync.RWMutex
+	themes      = map[string]Theme{
+		ThemeClassic: {Left: BarLeftDefault, Right: BarRightDefault, Completed: BarCompletedDefault, Uncompleted: BarUnCompletedDefault},
+		ThemeBlocks:  {Left: '▕', Right: '▏', Completed: '█', Uncompleted: ' ', Partials: smoothBlocks},
+		ThemeShaded:  {Left: '▕', Right: '▏', Completed: '█', Uncompleted: '░', Partials: []rune{'▒', '▓', '█'}},
+		ThemeDots:    {Left: '[', Right: ']', Completed: '●', Uncompleted: '·'},
+		ThemeArrows:  {Left: '[', Right: ']', Completed: '=', Uncompleted: ' ', Head: ">"},
+	}
+)
+
+// RegisterTheme makes a theme available to WithTheme, replacing any theme of the same name.
+func RegisterTheme(name string, theme Theme) {
+	themesMutex.Lock()
+	defer themesMutex.Unlock()
+
+	themes[name] = theme
+}
+
+// LookupTheme returns the registered theme of the given name.
+func LookupTheme(name string) (Theme, bool) {
+	themesMutex.RLock()
+	defer themesMutex.RUnlock()
+
+	theme, found := themes[name]
+	return theme, found
+}
+
+// WithTheme applies the registered theme of the given name. It panics if no such
+// theme has been registered, as a misspelled name is a programming error.
+func WithTheme(name string) Option {
+	theme, found := LookupTheme(name)
+	if !found {
+		panic(fmt.Sprintf("pbar: unknown theme %q", name))
+	}
+
+	return func(c *PBarSupport) {
+		c.barLeft, c.barRight = theme.Left, theme.Right
+		c.barCompleted, c.barUncompleted = theme.Completed, theme.Uncompleted
+		c.partials = theme.Partials
+		c.barHead, c.barFullHead = theme.Head, theme.FullHead
+		c.colors.completed = theme.CompletedColor
+		c.colors.uncompleted = theme.UncompletedColor
+		c.colors.brackets = theme.BracketColor
+	}
+}
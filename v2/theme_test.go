@@ -0,0 +1,49 @@
+package pbar
+
+import (
+	"testing"
+
+	"github.com/smarty/assertions/should"
+	"github.com/smarty/gunit"
+)
+
+func TestThemeFixture(t *testing.T) {
+	gunit.Run(new(ThemeFixture), t)
+}
+
+type ThemeFixture struct {
+	*gunit.Fixture
+}
+
+func (this *ThemeFixture) render(current int, options ...Option) string {
+	progressBar := NewPBar(100, append([]Option{BarLength(4)}, options...)...)
+	progressBar.initializeBar()
+	progressBar.Update(current)
+	progressBar.updateBar()
+	return progressBar.bar()
+}
+
+func (this *ThemeFixture) TestBuiltInThemes() {
+	this.So(this.render(60, WithTheme(ThemeClassic)), should.Equal, "[==  ]")
+	this.So(this.render(60, WithTheme(ThemeBlocks)), should.Equal, "▕██▍ ▏")
+	this.So(this.render(60, WithTheme(ThemeShaded)), should.Equal, "▕██▒░▏")
+	this.So(this.render(60, WithTheme(ThemeDots)), should.Equal, "[●●··]")
+	this.So(this.render(60, WithTheme(ThemeArrows)), should.Equal, "[=>  ]")
+}
+
+func (this *ThemeFixture) TestLaterOptionsOverrideTheTheme() {
+	this.So(this.render(50, WithTheme(ThemeDots), BarLeft('<')), should.Equal, "<●●··]")
+}
+
+func (this *ThemeFixture) TestCustomThemes() {
+	RegisterTheme("test-hash", Theme{Left: '|', Right: '|', Completed: '#', Uncompleted: '-', CompletedColor: Green})
+
+	theme, found := LookupTheme("test-hash")
+	this.So(found, should.BeTrue)
+	this.So(theme.CompletedColor, should.Equal, Green)
+	this.So(this.render(50, WithTheme("test-hash")), should.Equal, "|##--|")
+}
+
+func (this *ThemeFixture) TestUnknownThemePanics() {
+	this.So(func() { WithTheme("no-such-theme") }, should.PanicWith, `pbar: unknown theme "no-such-theme"`)
+}
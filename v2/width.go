@@ -0,0 +1,306 @@
+package pbar
+
+import (
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// fitWidth sizes the bar to fill the terminal columns left over by the rest of the line.
+// After a resize the bar takes all of that space; between resizes it only ever shrinks,
+// so that growing counts and statistics do not make its length jitter. When the rest of
+// the line does not fit on its own, the label is truncated.
+func (this *PBarSupport) fitWidth() {
+	if this.columns <= 0 || this.hidden {
+		return
+	}
+
+	// the rest of the line, plus the trailing space written by repaint
+	this.labelOverflow = 0
+	overhead := displayWidth(this.line()) - displayWidth(this.bar()) + 1
+	markers := runeWidth(this.barLeft) + runeWidth(this.barRight)
+	available := max(this.columns-overhead-markers, 0) / max(this.cellWidth, 1)
+	if this.fittedColumns == this.columns {
+		available = min(available, this.barLength)
+	}
+	this.labelOverflow = max(overhead+markers-this.columns, 0)
+
+	this.setBarLength(available)
+	this.fittedColumns = this.columns
+}
+
+// rowsFor reports the terminal rows that text of the given display width wraps onto.
+func (this *PBarSupport) rowsFor(width int) int {
+	if this.columns <= 0 || width <= this.columns {
+		return 1
+	}
+	return (width + this.columns - 1) / this.columns
+}
+
+// cells renders runes of the bar, padding each to the width of the widest rune the bar
+// is drawn with so that the bar keeps its width whichever runes fill it.
+func (this *PBarSupport) cells(runes []rune) string {
+	if this.cellWidth <= 1 {
+		return string(runes)
+	}
+
+	var cells strings.Builder
+	for _, r := range runes {
+		cells.WriteRune(r)
+		cells.WriteString(strings.Repeat(" ", max(this.cellWidth-runeWidth(r), 0)))
+	}
+	return cells.String()
+}
+
+// measureCells records the width of the widest rune the cells of the bar may be drawn with.
+func (this *PBarSupport) measureCells() {
+	this.cellWidth = 1
+	for _, r := range append([]rune{this.barCompleted, this.barUncompleted, this.barAbortedCompleted, this.barFailedCompleted}, this.partials...) {
+		this.cellWidth = max(this.cellWidth, runeWidth(r))
+	}
+}
+
+// displayWidth counts the terminal cells text occupies, skipping ANSI escape sequences.
+func displayWidth(text string) int {
+	width := 0
+	for i := 0; i < len(text); {
+		if text[i] == 27 && i+1 < len(text) && text[i+1] == '[' {
+			i += 2
+			for i < len(text) && (text[i] < 0x40 || text[i] > 0x7e) {
+				i++ // parameter and intermediate bytes
+			}
+			i++ // final byte
+			continue
+		}
+		size, cells := nextGrapheme(text[i:])
+		i += size
+		width += cells
+	}
+	return width
+}
+
+// truncateWidth shortens plain text to at most width cells, replacing the text cut
+// from its start, middle or end with an ellipsis.
+func truncateWidth(text string, width int, truncation Truncation) string {
+	if displayWidth(text) <= width {
+		return text
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	kept := width - 1 // leave room for the ellipsis
+	switch truncation {
+	case TruncateStart:
+		return ellipsis + leadingCells(text, kept, true)
+	case TruncateMiddle:
+		return leadingCells(text, kept-kept/2, false) + ellipsis + leadingCells(text, kept/2, true)
+	default:
+		return leadingCells(text, kept, false) + ellipsis
+	}
+}
+
+// leadingCells returns as many whole characters from the start (or, reversed, the end)
+// of text as fit in width cells.
+func leadingCells(text string, width int, reversed bool) string {
+	var bounds []int // the byte offset at which each character starts
+	for i := 0; i < len(text); {
+		size, _ := nextGrapheme(text[i:])
+		bounds = append(bounds, i)
+		i += size
+	}
+	bounds = append(bounds, len(text))
+
+	used := 0
+	if reversed {
+		start := len(bounds) - 1
+		for start > 0 && used+displayWidth(text[bounds[start-1]:bounds[start]]) <= width {
+			used += displayWidth(text[bounds[start-1]:bounds[start]])
+			start--
+		}
+		return text[bounds[start]:]
+	}
+
+	end := 0
+	for end < len(bounds)-1 && used+displayWidth(text[bounds[end]:bounds[end+1]]) <= width {
+		used += displayWidth(text[bounds[end]:bounds[end+1]])
+		end++
+	}
+	return text[:bounds[end]]
+}
+
+const ellipsis = "…"
+
+// nextGrapheme measures the user-perceived character at the start of text: a rune followed by
+// any combining marks, variation selectors and emoji modifiers, runes joined to it with a zero
+// width joiner, or a pair of regional indicators forming a flag. It returns the size in bytes
+// and the number of terminal cells the character occupies.
+func nextGrapheme(text string) (size, width int) {
+	r, size := utf8.DecodeRuneInString(text)
+	width = runeWidth(r)
+
+	if isRegionalIndicator(r) {
+		if next, n := utf8.DecodeRuneInString(text[size:]); isRegionalIndicator(next) {
+			return size + n, 2
+		}
+		return size, width
+	}
+
+	for size < len(text) {
+		next, n := utf8.DecodeRuneInString(text[size:])
+		switch {
+		case next == zeroWidthJoiner:
+			size += n
+			if size < len(text) {
+				_, n = utf8.DecodeRuneInString(text[size:])
+				size += n // the joined rune is drawn within the same cells
+			}
+		case next == emojiPresentation:
+			size += n
+			width = max(width, 2)
+		case isExtender(next):
+			size += n
+		default:
+			return size, width
+		}
+	}
+	return size, width
+}
+
+const (
+	zeroWidthJoiner   = '\u200D'
+	emojiPresentation = '\uFE0F' // requests that the preceding rune be drawn as a (wide) emoji
+)
+
+// isExtender reports whether r modifies the preceding rune rather than starting a new character.
+func isExtender(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me) ||
+		(r >= 0xFE00 && r <= 0xFE0F) || // variation selectors
+		(r >= 0x1F3FB && r <= 0x1F3FF) || // emoji skin tone modifiers
+		(r >= 0xE0020 && r <= 0xE007F) || // tags
+		(r >= 0xE0100 && r <= 0xE01EF) // variation selectors supplement
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// runeWidth returns the number of terminal cells r occupies on its own: none for control
+// characters, combining marks and other invisible runes, two for the wide runes of East
+// Asian scripts and emoji, and one otherwise.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7F && r < 0xA0):
+		return 0
+	case r >= 0x200B && r <= 0x200F, r == 0x2060, r == 0xFEFF, r == 0xAD:
+		return 0 // zero width spaces, joiners and marks, word joiner, byte order mark, soft hyphen
+	case isExtender(r):
+		return 0
+	}
+
+	for _, wide := range wideRunes {
+		if r < wide[0] {
+			break
+		}
+		if r <= wide[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// wideRunes are the ranges of runes that occupy two terminal cells, in ascending order.
+var wideRunes = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo initial consonants
+	{0x231A, 0x231B},   // watch, hourglass
+	{0x2329, 0x232A},   // angle brackets
+	{0x23E9, 0x23EC},   // media controls
+	{0x23F0, 0x23F0},   // alarm clock
+	{0x23F3, 0x23F3},   // hourglass with flowing sand
+	{0x25FD, 0x25FE},   // medium small squares
+	{0x2614, 0x2615},   // umbrella, hot beverage
+	{0x2648, 0x2653},   // zodiac
+	{0x267F, 0x267F},   // wheelchair
+	{0x2693, 0x2693},   // anchor
+	{0x26A1, 0x26A1},   // high voltage
+	{0x26AA, 0x26AB},   // medium circles
+	{0x26BD, 0x26BE},   // soccer ball, baseball
+	{0x26C4, 0x26C5},   // snowman, sun behind cloud
+	{0x26CE, 0x26CE},   // ophiuchus
+	{0x26D4, 0x26D4},   // no entry
+	{0x26EA, 0x26EA},   // church
+	{0x26F2, 0x26F3},   // fountain, golf
+	{0x26F5, 0x26F5},   // sailboat
+	{0x26FA, 0x26FA},   // tent
+	{0x26FD, 0x26FD},   // fuel pump
+	{0x2705, 0x2705},   // check mark button
+	{0x270A, 0x270B},   // raised fists
+	{0x2728, 0x2728},   // sparkles
+	{0x274C, 0x274C},   // cross mark
+	{0x274E, 0x274E},   // cross mark button
+	{0x2753, 0x2755},   // question and exclamation marks
+	{0x2757, 0x2757},   // exclamation mark
+	{0x2795, 0x2797},   // plus, minus, divide
+	{0x27B0, 0x27B0},   // curly loop
+	{0x27BF, 0x27BF},   // double curly loop
+	{0x2B1B, 0x2B1C},   // large squares
+	{0x2B50, 0x2B50},   // star
+	{0x2B55, 0x2B55},   // hollow circle
+	{0x2E80, 0x303E},   // CJK radicals, symbols and punctuation
+	{0x3041, 0x33FF},   // Hiragana, Katakana, Bopomofo, Hangul compatibility, CJK compatibility
+	{0x3400, 0x4DBF},   // CJK unified ideographs extension A
+	{0x4E00, 0x9FFF},   // CJK unified ideographs
+	{0xA000, 0xA4CF},   // Yi
+	{0xA960, 0xA97F},   // Hangul Jamo extended A
+	{0xAC00, 0xD7A3},   // Hangul syllables
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs
+	{0xFE10, 0xFE19},   // vertical forms
+	{0xFE30, 0xFE6F},   // CJK compatibility and small forms
+	{0xFF00, 0xFF60},   // fullwidth forms
+	{0xFFE0, 0xFFE6},   // fullwidth signs
+	{0x16FE0, 0x18CFF}, // Tangut, Khitan
+	{0x1B000, 0x1B2FF}, // Kana supplement and extensions, Nushu
+	{0x1F004, 0x1F004}, // mahjong tile
+	{0x1F0CF, 0x1F0CF}, // playing card black joker
+	{0x1F18E, 0x1F18E}, // AB button
+	{0x1F191, 0x1F19A}, // squared words
+	{0x1F200, 0x1F2FF}, // enclosed ideographic supplement
+	{0x1F300, 0x1F64F}, // pictographs and emoticons
+	{0x1F680, 0x1F6FF}, // transport and map symbols
+	{0x1F7E0, 0x1F7EB}, // colored circles and squares
+	{0x1F90C, 0x1F9FF}, // supplemental symbols and pictographs
+	{0x1FA70, 0x1FAFF}, // symbols and pictographs extended A
+	{0x20000, 0x3FFFD}, // CJK unified ideographs extensions B and beyond
+}
+
+// watchWidth measures the terminal, which AutoWidth bars fill and long lines wrap in, and
+// returns a channel that receives SIGWINCH, along with a func that stops the notifications.
+// [locks mutex]
+func (this *PBar[T]) watchWidth() (<-chan os.Signal, func()) {
+	this.mutex.Lock()
+	enabled := this.interactive
+	this.mutex.Unlock()
+	if !enabled {
+		return nil, func() {}
+	}
+
+	this.measureWidth()
+	return watchResize()
+}
+
+// [locks mutex]
+func (this *PBar[T]) measureWidth() {
+	if columns, err := terminalColumns(this.tty); err == nil {
+		this.resize(columns)
+	}
+}
+
+// resize records the width of the terminal, which applies from the next frame.
+// [locks mutex]
+func (this *PBar[T]) resize(columns int) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.columns = columns
+}
@@ -0,0 +1,21 @@
+//go:build !unix
+
+package pbar
+
+import (
+	"errors"
+	"os"
+)
+
+func terminalColumns(string) (int, error) {
+	return 0, errors.New("pbar: terminal width is not supported on this platform")
+}
+
+func isTerminalFile(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func watchResize() (<-chan os.Signal, func()) {
+	return nil, func() {}
+}
@@ -0,0 +1,129 @@
+package pbar
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/smarty/assertions/should"
+	"github.com/smarty/gunit"
+)
+
+func TestWidthFixture(t *testing.T) {
+	gunit.Run(new(WidthFixture), t)
+}
+
+type WidthFixture struct {
+	*gunit.Fixture
+}
+
+func (this *WidthFixture) TestBarFillsTheTerminal() {
+	progressBar := NewPBar(1000, AutoWidth(), BarLabel("load "))
+	progressBar.resize(40)
+	progressBar.initializeBar()
+
+	this.So(progressBar.line(), should.Equal, "load ["+strings.Repeat(" ", 19)+"] (0/1,000) 0%")
+	this.So(utf8.RuneCountInString(progressBar.line())+1, should.Equal, 40) // plus the trailing space
+}
+
+func (this *WidthFixture) TestBarOnlyShrinksBetweenResizes() {
+	progressBar := NewPBar(1000, AutoWidth())
+	progressBar.resize(30)
+	progressBar.initializeBar()
+	this.So(progressBar.barLength, should.Equal, 14)
+
+	progressBar.Update(999)
+	progressBar.updateBar()
+	this.So(progressBar.barLength, should.Equal, 11)
+
+	progressBar.Update(5)
+	progressBar.updateBar()
+	this.So(progressBar.barLength, should.Equal, 11)
+
+	progressBar.resize(50)
+	progressBar.updateBar()
+	this.So(progressBar.barLength, should.Equal, 34)
+
+	progressBar.resize(10)
+	progressBar.updateBar()
+	this.So(progressBar.barLength, should.Equal, 0)
+	this.So(progressBar.line(), should.Equal, "[] (5/1,000) 0%")
+}
+
+func (this *WidthFixture) TestWithoutTerminalWidthBarLengthApplies() {
+	progressBar := NewPBar(1000, AutoWidth(), BarLength(7))
+	progressBar.initializeBar()
+
+	this.So(progressBar.barLength, should.Equal, 7)
+}
+
+func (this *WidthFixture) TestContainerPassesWidthToNewBars() {
+	container := NewContainer(OutputWriter(io.Discard))
+	container.columns = 30
+	progressBar := NewPBar(10, AutoWidth())
+	container.Add(progressBar)
+
+	this.So(progressBar.frame(), should.Equal, "["+strings.Repeat(" ", 17)+"] (0/10) 0%")
+}
+
+func (this *WidthFixture) TestDisplayWidth() {
+	this.So(displayWidth("plain"), should.Equal, 5)
+	this.So(displayWidth("\x1b[32mgreen\x1b[0m"), should.Equal, 5)
+	this.So(displayWidth("日本語"), should.Equal, 6)
+	this.So(displayWidth("cafe\u0301"), should.Equal, 4)                  // combining acute accent
+	this.So(displayWidth("👩‍👩‍👧"), should.Equal, 2)                       // family joined with zero width joiners
+	this.So(displayWidth("👍🏽"), should.Equal, 2)                          // skin tone modifier
+	this.So(displayWidth("🇳🇿"), should.Equal, 2)                          // flag
+	this.So(displayWidth("❤️"), should.Equal, 2)                          // heart with emoji presentation
+	this.So(displayWidth("zero\u200bwidth\u00adspace"), should.Equal, 14) // zero width space and soft hyphen
+}
+
+func (this *WidthFixture) TestTruncateWidth() {
+	this.So(truncateWidth("short", 5, TruncateEnd), should.Equal, "short")
+	this.So(truncateWidth("longer", 5, TruncateEnd), should.Equal, "long…")
+	this.So(truncateWidth("日本語", 4, TruncateEnd), should.Equal, "日…")
+	this.So(truncateWidth("cafe\u0301s", 5, TruncateEnd), should.Equal, "cafe\u0301s")
+	this.So(truncateWidth("cafe\u0301s", 4, TruncateEnd), should.Equal, "caf…")
+	this.So(truncateWidth("anything", 0, TruncateEnd), should.Equal, "")
+
+	this.So(truncateWidth("/var/log/service.log", 13, TruncateStart), should.Equal, "…/service.log")
+	this.So(truncateWidth("/var/log/service.log", 13, TruncateMiddle), should.Equal, "/var/l…ce.log")
+	this.So(truncateWidth("日本語テキスト", 7, TruncateMiddle), should.Equal, "日…ト") // whole characters only
+}
+
+func (this *WidthFixture) TestWideBarRunesKeepTheBarWidth() {
+	progressBar := NewPBar(100, BarLength(4), BarCompleted('🟩'))
+	progressBar.initializeBar()
+	progressBar.Update(50)
+	progressBar.updateBar()
+
+	this.So(progressBar.bar(), should.Equal, "[🟩🟩    ]")
+	this.So(displayWidth(progressBar.bar()), should.Equal, 10)
+}
+
+func (this *WidthFixture) TestWideBarRunesFillTheTerminal() {
+	progressBar := NewPBar(1000, AutoWidth(), BarCompleted('🟩'), BarLabel("load "))
+	progressBar.resize(40)
+	progressBar.initializeBar()
+
+	this.So(progressBar.barLength, should.Equal, 9)
+	this.So(displayWidth(progressBar.line())+1, should.Equal, 39) // an odd column is left over
+}
+
+func (this *WidthFixture) TestWideLabelIsMeasuredByDisplayWidth() {
+	progressBar := NewPBar(1000, AutoWidth(), BarLabel("読み込み "))
+	progressBar.resize(40)
+	progressBar.initializeBar()
+
+	this.So(displayWidth(progressBar.line())+1, should.Equal, 40)
+}
+
+func (this *WidthFixture) TestLabelIsTruncatedToFitTheTerminal() {
+	progressBar := NewPBar(1000, AutoWidth(), BarLabel("/var/log/service.log "))
+	progressBar.resize(30)
+	progressBar.initializeBar()
+
+	this.So(progressBar.line(), should.Equal, "/var/log/serv…[] (0/1,000) 0%")
+	this.So(displayWidth(progressBar.line())+1, should.Equal, 30)
+}
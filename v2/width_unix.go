@@ -0,0 +1,40 @@
+//go:build unix
+
+package pbar
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminalColumns reports the width of the terminal device at path.
+func terminalColumns(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = file.Close() }()
+
+	size, err := unix.IoctlGetWinsize(int(file.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, err
+	}
+	return int(size.Col), nil
+}
+
+// isTerminalFile reports whether file is a terminal, which, unlike other character
+// devices such as /dev/null, has terminal attributes.
+func isTerminalFile(file *os.File) bool {
+	_, err := unix.IoctlGetTermios(int(file.Fd()), ioctlReadTermios)
+	return err == nil
+}
+
+// watchResize relays SIGWINCH until the returned func is called.
+func watchResize() (<-chan os.Signal, func()) {
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, syscall.SIGWINCH)
+	return resized, func() { signal.Stop(resized) }
+}
@@ -0,0 +1,100 @@
+package pbar
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// lineWriter buffers writes until they complete one or more lines, which it hands to print.
+type lineWriter struct {
+	mutex   sync.Mutex
+	pending []byte
+	print   func(lines []byte)
+}
+
+func (this *lineWriter) Write(p []byte) (int, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	this.pending = append(this.pending, p...)
+	end := bytes.LastIndexByte(this.pending, '\n')
+	if end < 0 {
+		return len(p), nil
+	}
+
+	this.print(this.pending[:end+1])
+	this.pending = append(this.pending[:0], this.pending[end+1:]...)
+	return len(p), nil
+}
+
+// Writer returns an io.Writer that prints complete lines above the bar and then repaints it,
+// e.g. for log.SetOutput, so that messages do not garble the bar. A partial line is held
+// until it is completed. The lines of a bar in a Container are printed as by its Bypass.
+func (this *PBar[T]) Writer() io.Writer {
+	return &lineWriter{print: func(lines []byte) {
+		if owner := this.owner.Load(); owner != nil {
+			owner.printAround(func() { _, _ = owner.output.Write(lines) })
+		} else {
+			this.printAround(func() { _, _ = this.output.Write(lines) })
+		}
+	}}
+}
+
+// printAround clears the bar's line, calls print to write in its place and repaints the bar below,
+// or has the Container that paints the bar do so for its block of bars.
+// [locks mutex]
+func (this *PBar[T]) printAround(print func()) {
+	if owner := this.owner.Load(); owner != nil {
+		owner.printAround(print)
+		return
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.plain || !this.running {
+		print() // there is no bar on the line to make way for
+		return
+	}
+
+	_, _ = io.WriteString(this.output, this.clearFrame())
+	print()
+
+	var frame strings.Builder
+	this.paintLine(&frame)
+	_, _ = io.WriteString(this.output, frame.String())
+}
+
+// Bypass returns an io.Writer that prints complete lines above the Container's bars and then
+// repaints them, e.g. for log.SetOutput, so that messages do not garble the bars. A partial
+// line is held until it is completed.
+func (this *Container) Bypass() io.Writer {
+	return &lineWriter{print: func(lines []byte) {
+		this.printAround(func() { _, _ = this.output.Write(lines) })
+	}}
+}
+
+// printAround erases the block of bars, calls print to write in its place and repaints the block below.
+// [locks mutex]
+func (this *Container) printAround(print func()) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if this.plain || !this.running || this.closed {
+		print() // there are no bars on the lines to make way for
+		return
+	}
+
+	if this.lines > 0 {
+		_, _ = fmt.Fprintf(this.output, "%c[%dA", 27, this.lines) // back to the first line of the block
+	}
+	_, _ = fmt.Fprintf(this.output, "%c%c[J", 13, 27)
+	print()
+
+	var frame strings.Builder
+	this.paintBars(&frame, Bar.lastFrame)
+	_, _ = io.WriteString(this.output, frame.String())
+}
@@ -0,0 +1,120 @@
+package pbar
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/smarty/assertions/should"
+	"github.com/smarty/gunit"
+)
+
+func TestWriterFixture(t *testing.T) {
+	gunit.Run(new(WriterFixture), t)
+}
+
+type WriterFixture struct {
+	*gunit.Fixture
+	output *bytes.Buffer
+}
+
+func (this *WriterFixture) Setup() {
+	this.output = new(bytes.Buffer)
+}
+
+func (this *WriterFixture) TestPartialLinesAreHeldUntilComplete() {
+	var printed []string
+	writer := &lineWriter{print: func(lines []byte) { printed = append(printed, string(lines)) }}
+
+	_, _ = writer.Write([]byte("one"))
+	_, _ = writer.Write([]byte(" two\nthree\nfo"))
+	_, _ = writer.Write([]byte("ur\n"))
+
+	this.So(printed, should.Resemble, []string{"one two\nthree\n", "four\n"})
+}
+
+func (this *WriterFixture) TestLinesArePrintedAboveTheBar() {
+	progressBar := NewPBar(10, BarLength(4), OutputWriter(this.output))
+	progressBar.initializeBar()
+	progressBar.running = true
+
+	_, _ = progressBar.Writer().Write([]byte("hello\n"))
+
+	this.So(this.output.String(), should.Equal, "\r\x1b[2Khello\n[    ] (0/10) 0% ")
+}
+
+func (this *WriterFixture) TestLinesPassThroughBeforeTheBarStarts() {
+	progressBar := NewPBar(10, BarLength(4), OutputWriter(this.output))
+
+	_, _ = progressBar.Writer().Write([]byte("hello\n"))
+
+	this.So(this.output.String(), should.Equal, "hello\n")
+}
+
+func (this *WriterFixture) TestLinesArePrintedAboveTheContainer() {
+	container := NewContainer(OutputWriter(this.output))
+	container.Add(NewPBar(10, BarLabel("one "), BarLength(4)))
+	container.Add(NewPBar(10, BarLabel("two "), BarLength(4)))
+	container.running = true
+	container.repaint()
+	this.output.Reset()
+
+	_, _ = container.Bypass().Write([]byte("hello\n"))
+
+	this.So(this.output.String(), should.Equal, ""+
+		"\x1b[2A\r\x1b[Jhello\n"+
+		"\r\x1b[2Kone [    ] (0/10) 0%\n"+
+		"\r\x1b[2Ktwo [    ] (0/10) 0%\n")
+}
+
+func (this *WriterFixture) TestLinesOfContainedBarsArePrintedAboveTheContainer() {
+	container := NewContainer(OutputWriter(this.output))
+	progressBar := NewPBar(10, BarLength(4))
+	container.Add(progressBar)
+	container.running = true
+	container.repaint()
+	this.output.Reset()
+
+	_, _ = progressBar.Writer().Write([]byte("hello\n"))
+
+	this.So(this.output.String(), should.Equal, ""+
+		"\x1b[1A\r\x1b[Jhello\n"+
+		"\r\x1b[2K[    ] (0/10) 0%\n")
+}
+
+func (this *WriterFixture) TestLinesFollowTheStoppedContainer() {
+	container := NewContainer(OutputWriter(this.output))
+	container.Add(NewPBar(10, BarLength(4)))
+	container.Start()
+	container.Stop()
+	this.output.Reset()
+
+	_, _ = container.Bypass().Write([]byte("hello\n"))
+
+	this.So(this.output.String(), should.Equal, "hello\n")
+}
+
+func (this *WriterFixture) TestLoggingWhileRunning() {
+	progressBar := NewPBar(100, BarLength(4), OutputWriter(this.output), RefreshIntervalMilliseconds(1))
+	logger := log.New(progressBar.Writer(), "", 0)
+	progressBar.Start()
+
+	var waiter sync.WaitGroup
+	for range 4 {
+		waiter.Add(1)
+		go func() {
+			defer waiter.Done()
+			for range 25 {
+				logger.Println("working")
+				progressBar.Increment()
+			}
+		}()
+	}
+	waiter.Wait()
+	progressBar.Finish()
+
+	this.So(strings.Count(this.output.String(), "\r\x1b[2Kworking\n"), should.Equal, 100)
+	this.So(this.output.String(), should.EndWith, "[====] (100/100) 100% ")
+}